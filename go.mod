@@ -1,3 +1,8 @@
 module github.com/gmdance/pay
 
-go 1.12
+go 1.18
+
+require (
+	golang.org/x/crypto v0.24.0
+	golang.org/x/text v0.16.0
+)
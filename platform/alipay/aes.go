@@ -0,0 +1,57 @@
+package alipay
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+)
+
+//AES-CBC加密，PKCS5填充，IV为全0，返回Base64编码的密文
+func AESEncrypt(data []byte, key string) (string, error) {
+	block, err := newAESCipher(key)
+	if err != nil {
+		return "", err
+	}
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	data = append(data, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	encrypted := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(encrypted, data)
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+//解密Base64编码的AES-CBC密文
+func AESDecrypt(content string, key string) ([]byte, error) {
+	block, err := newAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) == 0 || len(encrypted)%aes.BlockSize != 0 {
+		return nil, errors.New("aes content error")
+	}
+	data := make([]byte, len(encrypted))
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(data, encrypted)
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(data) {
+		return nil, errors.New("aes padding error")
+	}
+	return data[:len(data)-padding], nil
+}
+
+//key为支付宝开放平台生成的Base64编码的AES密钥
+func newAESCipher(key string) (cipher.Block, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.New("aes key error")
+	}
+	block, err := aes.NewCipher(keyBytes)
+	if err != nil {
+		return nil, errors.New("aes key error")
+	}
+	return block, nil
+}
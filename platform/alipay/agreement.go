@@ -0,0 +1,183 @@
+package alipay
+
+import "errors"
+
+//支付宝个人代扣协议（周期扣款）
+type AgreementAccessParams struct {
+	Channel string `json:"channel"` //签约接入方式 ALIPAYAPP/QRCODE/QRCODEORSMS
+}
+
+type AgreementPeriodRuleParams struct {
+	PeriodType    string `json:"period_type"`              //周期类型 DAY/MONTH 必填
+	Period        int    `json:"period"`                   //周期数 与period_type组合使用 必填
+	ExecuteTime   string `json:"execute_time"`             //首次扣款时间 yyyy-MM-dd 必填
+	SingleAmount  string `json:"single_amount"`            //单次扣款最大金额 必填
+	TotalAmount   string `json:"total_amount,omitempty"`   //周期内允许扣款的总金额
+	TotalPayments int    `json:"total_payments,omitempty"` //总扣款次数
+}
+
+type UserAgreementPageSignParams struct {
+	PersonalProductCode string                     `json:"personal_product_code"`           //个人签约产品码 CYCLE_PAY_AUTH_P
+	ProductCode         string                     `json:"product_code"`                    //销售产品码 CYCLE_PAY_AUTH
+	SignScene           string                     `json:"sign_scene,omitempty"`            //协议签约场景 如INDUSTRY|DIGITAL_MEDIA
+	ExternalAgreementNo string                     `json:"external_agreement_no,omitempty"` //商户签约号
+	ExternalLogonId     string                     `json:"external_logon_id,omitempty"`     //用户在商户网站的登录账号
+	AccessParams        *AgreementAccessParams     `json:"access_params"`                   //请求签约的接入方式 必填
+	PeriodRuleParams    *AgreementPeriodRuleParams `json:"period_rule_params,omitempty"`    //周期管控规则参数 周期扣款必填
+	SignValidityPeriod  string                     `json:"sign_validity_period,omitempty"`  //当前用户签约请求的协议有效周期 如2m、7d
+	ThirdPartyType      string                     `json:"third_party_type,omitempty"`      //签约第三方主体类型 PARTNER/MERCHANT
+	EffectTime          string                     `json:"effect_time,omitempty"`           //签约有效时间限制 单位秒
+	MerchantProcessUrl  string                     `json:"merchant_process_url,omitempty"`  //商户签约完成后的跳转地址
+	ReturnUrl           string                     `json:"-"`                               //签约完成后的同步跳转地址
+	NotifyUrl           string                     `json:"-"`                               //签约、解约结果异步通知地址 默认agreementNotifyURL
+}
+
+func (params UserAgreementPageSignParams) check() error {
+	if params.AccessParams == nil || params.AccessParams.Channel == "" {
+		return errors.New("accessParams.channel未填写")
+	}
+	if rule := params.PeriodRuleParams; rule != nil {
+		if rule.PeriodType == "" || rule.Period <= 0 {
+			return errors.New("periodRuleParams周期未填写")
+		}
+		if rule.ExecuteTime == "" {
+			return errors.New("periodRuleParams.executeTime未填写")
+		}
+		if rule.SingleAmount == "" {
+			return errors.New("periodRuleParams.singleAmount未填写")
+		}
+	}
+	return nil
+}
+
+//支付宝个人协议页面签约 返回GET跳转地址，App内可拼接alipays://platformapi/startapp?appId=60000157&appClearTop=false&startMultApp=YES&sign_params=后打开
+func (alipay *Alipay) UserAgreementPageSignURL(bizContent UserAgreementPageSignParams) (string, error) {
+	if err := bizContent.check(); err != nil {
+		return "", err
+	}
+	if bizContent.PersonalProductCode == "" {
+		bizContent.PersonalProductCode = PersonalProductCodeCyclePayAuthP
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodeCyclePayAuth
+	}
+	extParams := returnUrlParams(bizContent.ReturnUrl)
+	if bizContent.NotifyUrl != "" {
+		extParams["notify_url"] = bizContent.NotifyUrl
+	} else if alipay.conf.AgreementNotifyURL != "" {
+		extParams["notify_url"] = alipay.conf.AgreementNotifyURL
+	}
+	return alipay.BuildPageURL(MethodUserAgreementPageSign, bizContent, extParams)
+}
+
+//支付宝个人代扣协议查询
+type UserAgreementQueryParams struct {
+	AgreementNo         string `json:"agreement_no,omitempty"`          //支付宝系统中用以唯一标识用户签约记录的编号 与external_agreement_no二选一
+	ExternalAgreementNo string `json:"external_agreement_no,omitempty"` //商户签约号
+	PersonalProductCode string `json:"personal_product_code,omitempty"` //个人签约产品码
+	AlipayUserId        string `json:"alipay_user_id,omitempty"`        //用户的支付宝账号对应的支付宝唯一用户号
+	AlipayOpenId        string `json:"alipay_open_id,omitempty"`        //用户的支付宝账号对应的openId
+	AlipayLogonId       string `json:"alipay_logon_id,omitempty"`       //用户的支付宝登录账号
+	SignScene           string `json:"sign_scene,omitempty"`            //签约协议场景
+	ThirdPartyType      string `json:"third_party_type,omitempty"`      //签约第三方主体类型
+}
+
+func (params UserAgreementQueryParams) check() error {
+	if params.AgreementNo == "" && params.ExternalAgreementNo == "" {
+		return errors.New("agreementNo和externalAgreementNo必须填写一项")
+	}
+	return nil
+}
+
+type UserAgreementQueryResult struct {
+	Result
+	AgreementNo         string `json:"agreement_no"`          //用户签约成功后的协议号
+	ExternalAgreementNo string `json:"external_agreement_no"` //商户签约号
+	PersonalProductCode string `json:"personal_product_code"` //协议产品码
+	SignScene           string `json:"sign_scene"`            //签约协议的场景
+	Status              string `json:"status"`                //协议当前状态 TEMP暂存/NORMAL正常/STOP暂停
+	SignTime            string `json:"sign_time"`             //协议签约时间
+	ValidTime           string `json:"valid_time"`            //协议生效时间
+	InvalidTime         string `json:"invalid_time"`          //协议失效时间
+	AlipayLogonId       string `json:"alipay_logon_id"`       //用户签约的支付宝账号
+	PrincipalId         string `json:"principal_id"`          //签约主体标识
+	PrincipalOpenId     string `json:"principal_open_id"`     //签约主体openId
+	PricipalType        string `json:"pricipal_type"`         //签约主体类型 CARD/CUSTOMER
+	ThirdPartyType      string `json:"third_party_type"`      //签约第三方主体类型
+	ExternalLogonId     string `json:"external_logon_id"`     //用户在商户网站的登录账号
+	ZmOpenId            string `json:"zm_open_id"`            //用户的芝麻信用openId
+	CreditAuthMode      string `json:"credit_auth_mode"`      //授信模式
+	SingleQuota         string `json:"single_quota"`          //单笔代扣额度
+	DeviceId            string `json:"device_id"`             //设备Id
+	LastDeductTime      string `json:"last_deduct_time"`      //周期扣协议上次扣款成功时间
+	NextDeductTime      string `json:"next_deduct_time"`      //周期扣协议预计下次扣款时间
+}
+
+func (alipay *Alipay) UserAgreementQuery(bizContent UserAgreementQueryParams) (*UserAgreementQueryResult, string, error) {
+	if err := bizContent.check(); err != nil {
+		return nil, "", err
+	}
+	var result UserAgreementQueryResult
+	data, err := alipay.Request(MethodUserAgreementQuery, bizContent, &result)
+	return &result, data, err
+}
+
+//支付宝个人代扣协议解约
+type UserAgreementUnsignParams struct {
+	AgreementNo         string `json:"agreement_no,omitempty"`          //支付宝系统中用以唯一标识用户签约记录的编号 与external_agreement_no二选一
+	ExternalAgreementNo string `json:"external_agreement_no,omitempty"` //商户签约号
+	PersonalProductCode string `json:"personal_product_code,omitempty"` //个人签约产品码
+	AlipayUserId        string `json:"alipay_user_id,omitempty"`        //用户的支付宝账号对应的支付宝唯一用户号
+	AlipayOpenId        string `json:"alipay_open_id,omitempty"`        //用户的支付宝账号对应的openId
+	AlipayLogonId       string `json:"alipay_logon_id,omitempty"`       //用户的支付宝登录账号
+	SignScene           string `json:"sign_scene,omitempty"`            //签约协议场景
+	ThirdPartyType      string `json:"third_party_type,omitempty"`      //签约第三方主体类型
+	ExtendParams        string `json:"extend_params,omitempty"`         //扩展参数 如{"UNSIGN_ERROR_CODE":"USER_OWE_CODE"}
+	OperateType         string `json:"operate_type,omitempty"`          //操作类型 confirm解约确认/invalid解约作废
+}
+
+type UserAgreementUnsignResult struct {
+	Result
+}
+
+func (alipay *Alipay) UserAgreementUnsign(bizContent UserAgreementUnsignParams) (*UserAgreementUnsignResult, string, error) {
+	if bizContent.AgreementNo == "" && bizContent.ExternalAgreementNo == "" {
+		return nil, "", errors.New("agreementNo和externalAgreementNo必须填写一项")
+	}
+	var result UserAgreementUnsignResult
+	data, err := alipay.Request(MethodUserAgreementUnsign, bizContent, &result)
+	return &result, data, err
+}
+
+//周期性扣款协议执行计划修改 只能修改下一次扣款时间
+type UserAgreementExecutionplanModifyParams struct {
+	AgreementNo string `json:"agreement_no"`   //周期性扣款产品授权的协议号 必填
+	DeductTime  string `json:"deduct_time"`    //商户下一次扣款时间 yyyy-MM-dd 必填
+	Memo        string `json:"memo,omitempty"` //具体修改原因
+}
+
+type UserAgreementExecutionplanModifyResult struct {
+	Result
+	AgreementNo string `json:"agreement_no"` //周期性扣款产品授权的协议号
+	DeductTime  string `json:"deduct_time"`  //商户下一次扣款时间
+}
+
+func (alipay *Alipay) UserAgreementExecutionplanModify(bizContent UserAgreementExecutionplanModifyParams) (*UserAgreementExecutionplanModifyResult, string, error) {
+	if bizContent.AgreementNo == "" {
+		return nil, "", errors.New("agreementNo未填写")
+	}
+	if bizContent.DeductTime == "" {
+		return nil, "", errors.New("deductTime未填写")
+	}
+	var result UserAgreementExecutionplanModifyResult
+	data, err := alipay.Request(MethodUserAgreementExecutionplanModify, bizContent, &result)
+	return &result, data, err
+}
+
+//代扣支付时使用的协议参数
+type AgreementParams struct {
+	AgreementNo      string `json:"agreement_no"`                //支付宝系统中用以唯一标识用户签约记录的编号 必填
+	AuthConfirmNo    string `json:"auth_confirm_no,omitempty"`   //鉴权确认码
+	ApplyToken       string `json:"apply_token,omitempty"`       //鉴权申请token
+	DeductPermission string `json:"deduct_permission,omitempty"` //商户代扣扣款许可
+}
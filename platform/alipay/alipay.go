@@ -2,13 +2,17 @@ package alipay
 
 import (
 	"bytes"
+	"context"
+	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"github.com/gmdance/pay/utils"
+	"html"
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -20,48 +24,277 @@ const (
 
 	SignTypeRSA  = "RSA"
 	SignTypeRSA2 = "RSA2"
+	SignTypeMD5  = "MD5"
+
+	EnvProduction = "production"
+	EnvSandbox    = "sandbox"
+
+	EncryptTypeAES = "AES"
 
 	MainHost             = "https://openapi.alipay.com/gateway.do"
+	SandboxHost          = "https://openapi-sandbox.dl.alipaydev.com/gateway.do"
+	MapiHost             = "https://mapi.alipay.com/gateway.do"
+	MapiSandboxHost      = "https://mapi.alipaydev.com/gateway.do"
 	MethodTradePreCreate = "alipay.trade.precreate"
 	MethodTradeQuery     = "alipay.trade.query"
+
+	MethodTradeRefund             = "alipay.trade.refund"
+	MethodTradeFastpayRefundQuery = "alipay.trade.fastpay.refund.query"
+	MethodTradeClose              = "alipay.trade.close"
+	MethodTradeCancel             = "alipay.trade.cancel"
+	MethodTradePay                = "alipay.trade.pay"
+	MethodTradePagePay            = "alipay.trade.page.pay"
+	MethodTradeWapPay             = "alipay.trade.wap.pay"
+	MethodTradeAppPay             = "alipay.trade.app.pay"
+	MethodTradeCreate             = "alipay.trade.create"
+	MethodAlipayCertDownload      = "alipay.open.app.alipaycert.download"
+	MethodBillDownloadURLQuery    = "alipay.data.dataservice.bill.downloadurl.query"
+	MethodFundTransUniTransfer    = "alipay.fund.trans.uni.transfer"
+	MethodFundTransCommonQuery    = "alipay.fund.trans.common.query"
+	MethodFundAccountQuery        = "alipay.fund.account.query"
+
+	MethodTradeRoyaltyRelationBind   = "alipay.trade.royalty.relation.bind"
+	MethodTradeRoyaltyRelationUnbind = "alipay.trade.royalty.relation.unbind"
+	MethodTradeOrderSettle           = "alipay.trade.order.settle"
+	MethodTradeOrderSettleQuery      = "alipay.trade.order.settle.query"
+
+	MethodSystemOauthToken = "alipay.system.oauth.token"
+	MethodUserInfoShare    = "alipay.user.info.share"
+	MethodOpenAuthTokenApp = "alipay.open.auth.token.app"
+
+	MethodFundAuthOrderAppFreeze       = "alipay.fund.auth.order.app.freeze"
+	MethodFundAuthOrderVoucherCreate   = "alipay.fund.auth.order.voucher.create"
+	MethodFundAuthOrderUnfreeze        = "alipay.fund.auth.order.unfreeze"
+	MethodFundAuthOperationDetailQuery = "alipay.fund.auth.operation.detail.query"
+	MethodFundAuthOperationCancel      = "alipay.fund.auth.operation.cancel"
+
+	MethodUserAgreementPageSign            = "alipay.user.agreement.page.sign"
+	MethodUserAgreementQuery               = "alipay.user.agreement.query"
+	MethodUserAgreementUnsign              = "alipay.user.agreement.unsign"
+	MethodUserAgreementExecutionplanModify = "alipay.user.agreement.executionplan.modify"
+
+	MethodTradeMergePrecreate = "alipay.trade.merge.precreate"
+	MethodTradeAppMergePay    = "alipay.trade.app.merge.pay"
+	MethodTradeWapMergePay    = "alipay.trade.wap.merge.pay"
+
+	CodeSuccess        = "10000"
+	CodeWaitUserPay    = "10003"
+	CodeSystemError    = "20000"
+	CodeAuthFailed     = "20001"
+	CodeMissParams     = "40001"
+	CodeInvalidParams  = "40002"
+	CodeBusinessFailed = "40004"
+	CodeNoPermission   = "40006"
+
+	SceneBarCode  = "bar_code"
+	SceneFaceCode = "face_code"
+
+	ProductCodeFaceToFacePayment   = "FACE_TO_FACE_PAYMENT"
+	ProductCodeFastInstantTradePay = "FAST_INSTANT_TRADE_PAY"
+	ProductCodeQuickWapWay         = "QUICK_WAP_WAY"
+	ProductCodeQuickMsecurityPay   = "QUICK_MSECURITY_PAY"
+	ProductCodeJsapiPay            = "JSAPI_PAY"
+	ProductCodeTransAccountNoPwd   = "TRANS_ACCOUNT_NO_PWD"
+	ProductCodeTransBankcardNoPwd  = "TRANS_BANKCARD_NO_PWD"
+	ProductCodePreAuth             = "PRE_AUTH"
+	ProductCodePreAuthOnline       = "PRE_AUTH_ONLINE"
+	ProductCodeCyclePayAuth        = "CYCLE_PAY_AUTH"
+	ProductCodeGeneralWithholding  = "GENERAL_WITHHOLDING"
+
+	PersonalProductCodeCyclePayAuthP = "CYCLE_PAY_AUTH_P"
+
+	PayOutcomeSuccess   = "SUCCESS"
+	PayOutcomeClosed    = "CLOSED"
+	PayOutcomeCancelled = "CANCELLED"
+	PayOutcomeFailed    = "FAILED"
+
+	TradeStatusWaitBuyerPay = "WAIT_BUYER_PAY"
+	TradeStatusClosed       = "TRADE_CLOSED"
+	TradeStatusSuccess      = "TRADE_SUCCESS"
+	TradeStatusFinished     = "TRADE_FINISHED"
+
+	QueryOptionFundBillList      = "fund_bill_list"
+	QueryOptionVoucherDetailList = "voucher_detail_list"
+
+	RefundStatusSuccess = "REFUND_SUCCESS"
+
+	CancelActionClose  = "close"
+	CancelActionRefund = "refund"
+
+	BillTypeTrade        = "trade"
+	BillTypeSignCustomer = "signcustomer"
+
+	BizSceneDirectTransfer = "DIRECT_TRANSFER"
+
+	IdentityTypeAlipayUserId    = "ALIPAY_USER_ID"
+	IdentityTypeAlipayLogonId   = "ALIPAY_LOGON_ID"
+	IdentityTypeBankcardAccount = "BANKCARD_ACCOUNT"
+
+	AccountTypeAcctransAccount = "ACCTRANS_ACCOUNT"
+
+	TransStatusSuccess = "SUCCESS"
+	TransStatusDealing = "DEALING"
+	TransStatusFail    = "FAIL"
+	TransStatusRefund  = "REFUND"
+
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+
+	AuthConfirmModeComplete    = "COMPLETE"
+	AuthConfirmModeNotComplete = "NOT_COMPLETE"
+
+	AgreementStatusTemp   = "TEMP"
+	AgreementStatusNormal = "NORMAL"
+	AgreementStatusStop   = "STOP"
+
+	AgreementChannelAlipayApp = "ALIPAYAPP"
+	AgreementChannelQrcode    = "QRCODE"
+
+	PeriodTypeDay   = "DAY"
+	PeriodTypeMonth = "MONTH"
+
+	NotifyTypeUserSign   = "dut_user_sign"
+	NotifyTypeUserUnsign = "dut_user_unsign"
+
+	ServiceCreateForexTrade = "create_forex_trade"
+	ServiceForexRefund      = "forex_refund"
+	ServiceSingleTradeQuery = "single_trade_query"
+	ServiceNotifyVerify     = "notify_verify"
+
+	ProductCodeNewOverseasSeller    = "NEW_OVERSEAS_SELLER"
+	ProductCodeNewWapOverseasSeller = "NEW_WAP_OVERSEAS_SELLER"
 )
 
+//Alipay创建后只读，可并发使用
 type Alipay struct {
 	conf           Config
 	responseSuffix string
 	errorResponse  string
 	signNodeName   string
+	privateKey     *rsa.PrivateKey
+	publicKey      *rsa.PublicKey
+	appCertSN      string
+	rootCertSN     string
+	certs          *alipayCerts
+	appAuthToken   string
+	ctx            context.Context
+}
+
+//已加载的支付宝公钥证书 按序列号索引，证书轮换时会新增
+type alipayCerts struct {
+	sync.RWMutex
+	currentSN string
+	keys      map[string]*rsa.PublicKey
 }
 
 type Resp struct {
 	Sign string `json:"sign"`
 }
 
-func NewAlipay(conf Config) (*Alipay) {
-	return &Alipay{
+//创建时解析并缓存密钥与证书，配置错误直接返回
+func NewAlipay(conf Config) (*Alipay, error) {
+	alipay := &Alipay{
 		conf:           conf,
 		responseSuffix: "_response",
 		errorResponse:  "error_response",
 		signNodeName:   "sign",
+		certs:          &alipayCerts{keys: make(map[string]*rsa.PublicKey)},
+	}
+	if err := checkEnv(conf.Env); err != nil {
+		return nil, err
+	}
+	var err error
+	if conf.AppPrivateKey == "" && conf.AppPrivateKeyFile != "" {
+		alipay.privateKey, err = LoadPrivateKeyFile(conf.AppPrivateKeyFile, conf.AppPrivateKeyPassword)
+	} else {
+		alipay.privateKey, err = ParsePrivateKeyWithPassword(FormatPrivateKey(conf.AppPrivateKey), conf.AppPrivateKeyPassword)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if conf.AlipayPublicKey != "" {
+		if alipay.publicKey, err = ParsePublicKey(FormatPublicKey(conf.AlipayPublicKey)); err != nil {
+			return nil, err
+		}
+	}
+	if alipay.IsCertMode() {
+		certs, err := ParseCerts([]byte(conf.AppPublicCert))
+		if err != nil {
+			return nil, err
+		}
+		alipay.appCertSN = GetCertSN(certs[0])
+		if alipay.rootCertSN, err = GetRootCertSN([]byte(conf.AlipayRootCert)); err != nil {
+			return nil, err
+		}
+	}
+	if conf.AlipayPublicCert != "" {
+		if err = alipay.LoadAlipayPublicCert(conf.AlipayPublicCert); err != nil {
+			return nil, err
+		}
+	}
+	return alipay, nil
+}
+
+//支付宝网关地址 优先使用自定义网关，其次按运行环境选择
+//env为空时使用生产环境，拼写错误的env直接报错，避免误连生产网关
+func checkEnv(env string) error {
+	switch env {
+	case "", EnvProduction, EnvSandbox:
+		return nil
 	}
+	return errors.New("env仅支持production/sandbox: " + env)
+}
+
+func (alipay *Alipay) Gateway() string {
+	if alipay.conf.Gateway != "" {
+		return alipay.conf.Gateway
+	}
+	if alipay.conf.Env == EnvSandbox {
+		return SandboxHost
+	}
+	return MainHost
 }
 
 func (alipay *Alipay) BuildQuery(method string, bizContent interface{}) (url.Values, error) {
+	return alipay.BuildQueryWithParams(method, bizContent, nil)
+}
+
+//extParams为biz_content之外的公共请求参数，如return_url，会覆盖默认值
+//bizContent为nil时不传biz_content，如alipay.system.oauth.token
+func (alipay *Alipay) BuildQueryWithParams(method string, bizContent interface{}, extParams map[string]string) (url.Values, error) {
 	conf := alipay.conf
-	bizContentData, err := json.Marshal(bizContent)
-	if err != nil {
-		return nil, err
-	}
 	params := map[string]string{
-		"app_id":      conf.AppID,
-		"method":      method,
-		"format":      "JSON",
-		"charset":     "utf-8",
-		"sign_type":   conf.SignType,
-		"timestamp":   time.Now().Format("2006-01-02 03:04:05"),
-		"version":     "1.0",
-		"notify_url":  conf.PayNotifyURL,
-		"biz_content": string(bizContentData),
+		"app_id":     conf.AppID,
+		"method":     method,
+		"format":     "JSON",
+		"charset":    "utf-8",
+		"sign_type":  conf.SignType,
+		"timestamp":  time.Now().Format("2006-01-02 15:04:05"),
+		"version":    "1.0",
+		"notify_url": conf.PayNotifyURL,
+	}
+	if bizContent != nil {
+		bizContentData, err := json.Marshal(bizContent)
+		if err != nil {
+			return nil, err
+		}
+		params["biz_content"] = string(bizContentData)
+		if conf.EncryptKey != "" {
+			if params["biz_content"], err = AESEncrypt(bizContentData, conf.EncryptKey); err != nil {
+				return nil, err
+			}
+			params["encrypt_type"] = EncryptTypeAES
+		}
+	}
+	if alipay.appAuthToken != "" {
+		params["app_auth_token"] = alipay.appAuthToken
+	}
+	if alipay.IsCertMode() {
+		params["app_cert_sn"] = alipay.appCertSN
+		params["alipay_root_cert_sn"] = alipay.rootCertSN
+	}
+	for k, v := range extParams {
+		params[k] = v
 	}
 	sign, err := alipay.SignParams(params)
 	if err != nil {
@@ -75,7 +308,52 @@ func (alipay *Alipay) BuildQuery(method string, bizContent interface{}) (url.Val
 	return values, nil
 }
 
+//生成跳转到支付宝网关的GET地址
+func (alipay *Alipay) BuildPageURL(method string, bizContent interface{}, extParams map[string]string) (string, error) {
+	params, err := alipay.BuildQueryWithParams(method, bizContent, extParams)
+	if err != nil {
+		return "", err
+	}
+	return alipay.Gateway() + "?" + params.Encode(), nil
+}
+
+//生成自动提交到支付宝网关的POST表单
+func (alipay *Alipay) BuildPageForm(method string, bizContent interface{}, extParams map[string]string) (string, error) {
+	params, err := alipay.BuildQueryWithParams(method, bizContent, extParams)
+	if err != nil {
+		return "", err
+	}
+	return buildForm(alipay.Gateway()+"?charset=utf-8", params), nil
+}
+
+//生成自动提交的POST表单
+func buildForm(action string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var buff bytes.Buffer
+	buff.WriteString(`<form id="alipaysubmit" name="alipaysubmit" action="`)
+	buff.WriteString(html.EscapeString(action))
+	buff.WriteString(`" method="POST">`)
+	for _, k := range keys {
+		buff.WriteString(`<input type="hidden" name="`)
+		buff.WriteString(html.EscapeString(k))
+		buff.WriteString(`" value="`)
+		buff.WriteString(html.EscapeString(params.Get(k)))
+		buff.WriteString(`"/>`)
+	}
+	buff.WriteString(`<input type="submit" value="ok" style="display:none;"></form>`)
+	buff.WriteString(`<script>document.forms['alipaysubmit'].submit();</script>`)
+	return buff.String()
+}
+
 func (alipay *Alipay) GetSignContent(params map[string]string) []byte {
+	return getSignContent(params)
+}
+
+func getSignContent(params map[string]string) []byte {
 	keys := make([]string, 0)
 	for key, _ := range params {
 		keys = append(keys, key)
@@ -99,56 +377,97 @@ func (alipay *Alipay) GetSignContent(params map[string]string) []byte {
 }
 
 func (alipay *Alipay) SignParams(params map[string]string) (string, error) {
-	return OpenSSLSign(alipay.GetSignContent(params), alipay.conf.SignType, alipay.conf.AppPrivateKey)
+	return RSASign(alipay.GetSignContent(params), alipay.conf.SignType, alipay.privateKey)
+}
+
+func (alipay *Alipay) Request(method string, bizContent interface{}, resp interface{}) (data string, e error) {
+	return alipay.request(method, bizContent, nil, resp, true)
 }
 
-func (alipay *Alipay) ParseJsonResponse(responseContent, nodeName string, nodeIndex int) (string, string) {
-	signDataStartIndex := nodeIndex + len(nodeName) + 2;
-	signStartIndex := strings.LastIndex(responseContent, "\""+alipay.signNodeName+"\"")
-	signDataEndIndex := signStartIndex - 1
-	indexLen := signDataEndIndex - signDataStartIndex
-	if indexLen < 0 {
-		return "", ""
+//extParams为biz_content之外的请求参数，如grant_type、auth_token
+func (alipay *Alipay) RequestWithParams(method string, bizContent interface{}, extParams map[string]string, resp interface{}) (data string, e error) {
+	return alipay.request(method, bizContent, extParams, resp, true)
+}
+
+//校验支付宝签名，公钥证书模式下使用certSN对应的支付宝公钥证书
+func (alipay *Alipay) VerifySign(content []byte, sign, signType, certSN string) error {
+	signBytes, err := base64.StdEncoding.DecodeString(sign)
+	if err != nil {
+		return err
 	}
-	signData := responseContent[signDataStartIndex : signDataStartIndex+indexLen]
-	sign := responseContent[signStartIndex:]
-	signEndIndex := strings.LastIndex(sign, "\"}")
-	sign = sign[8:signEndIndex]
-	return signData, sign
+	if alipay.IsCertMode() {
+		key, err := alipay.certPublicKey(certSN)
+		if err != nil {
+			return err
+		}
+		return RSAVerify(content, signBytes, signType, key)
+	}
+	if alipay.publicKey == nil {
+		return errors.New("alipayPublicKey未配置")
+	}
+	return RSAVerify(content, signBytes, signType, alipay.publicKey)
 }
 
-func (alipay *Alipay) Request(method string, bizContent interface{}, resp interface{}) (data string, e error) {
-	params, err := alipay.BuildQuery(method, bizContent)
+//返回绑定ctx的客户端，ctx取消或超时时中断进行中的请求
+func (alipay *Alipay) WithContext(ctx context.Context) *Alipay {
+	client := *alipay
+	client.ctx = ctx
+	return &client
+}
+
+func (alipay *Alipay) request(method string, bizContent interface{}, extParams map[string]string, resp interface{}, verify bool) (data string, e error) {
+	params, err := alipay.BuildQueryWithParams(method, bizContent, extParams)
 	if err != nil {
 		return "", err
 	}
-	raw, err := utils.HttpGet(MainHost + "?" + params.Encode())
+	ctx := alipay.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	raw, err := utils.HttpGetContext(ctx, alipay.Gateway()+"?"+params.Encode())
 	if err != nil {
 		return "", err
 	}
 	data = string(raw)
 	rootNodeName := strings.Replace(method, ".", "_", -1) + alipay.responseSuffix
-	rootIndex := strings.Index(data, rootNodeName)
-	errorIndex := strings.Index(data, alipay.errorResponse)
-	content := ""
-	sign := ""
-	if rootIndex > 0 {
-		content, sign = alipay.ParseJsonResponse(data, rootNodeName, rootIndex)
-	} else {
-		content, sign = alipay.ParseJsonResponse(data, alipay.errorResponse, errorIndex)
+	response, err := alipay.ParseJsonResponse(raw, rootNodeName)
+	if err != nil {
+		return data, err
 	}
-	if alipay.conf.AlipayPublicKey != "" {
-		signBytes, err := base64.StdEncoding.DecodeString(sign)
-		err = OpenSSLVerify([]byte(content), signBytes, alipay.conf.SignType, alipay.conf.AlipayPublicKey)
+	content := response.Content
+	//网关直接拒绝的error_response可能不带签名，此时按失败处理且不验签
+	if verify && (alipay.publicKey != nil || alipay.IsCertMode()) && (response.Sign != "" || response.NodeName != alipay.errorResponse) {
+		if response.Sign == "" {
+			return data, errors.New("response error: sign not found")
+		}
+		err = alipay.VerifySign(content, response.Sign, alipay.conf.SignType, response.AlipayCertSn)
 		if err != nil {
 			return data, err
 		}
 	}
-	if strings.Index(data, "\"code\":\"10000\"") < 0 {
-		e = errors.New("not success")
+	//加密的响应内容为JSON字符串，验签后再解密
+	if alipay.conf.EncryptKey != "" && bytes.HasPrefix(content, []byte("\"")) {
+		var encrypted string
+		if err = json.Unmarshal(content, &encrypted); err != nil {
+			return data, err
+		}
+		content, err = AESDecrypt(encrypted, alipay.conf.EncryptKey)
+		if err != nil {
+			return data, err
+		}
+	}
+	var result AlipayError
+	if err = json.Unmarshal(content, &result); err != nil {
+		return data, err
 	}
 	if resp != nil {
-		e = json.Unmarshal([]byte(content), resp)
+		if err = json.Unmarshal(content, resp); err != nil {
+			return data, err
+		}
+	}
+	//部分接口成功时不返回code，如alipay.system.oauth.token
+	if response.NodeName == alipay.errorResponse || (result.Code != "" && result.Code != CodeSuccess) {
+		return data, &result
 	}
-	return
+	return data, nil
 }
@@ -0,0 +1,44 @@
+package alipay
+
+import "errors"
+
+//App支付
+type TradeAppPayParams struct {
+	OutTradeNo         string        `json:"out_trade_no"`                   //订单号 必填
+	TotalAmount        string        `json:"total_amount"`                   //订单金额 必填
+	Subject            string        `json:"subject"`                        //订单标题 必填
+	ProductCode        string        `json:"product_code"`                   //销售产品码 QUICK_MSECURITY_PAY
+	Body               string        `json:"body,omitempty"`                 //对商品的描述
+	GoodsDetail        []GoodsDetail `json:"goods_detail,omitempty"`         //订单包含的商品列表信息
+	TimeExpire         string        `json:"time_expire,omitempty"`          //订单绝对超时时间
+	TimeoutExpress     string        `json:"timeout_express,omitempty"`      //该笔订单允许的最晚付款时间
+	GoodsType          string        `json:"goods_type,omitempty"`           //商品主类型 0虚拟类商品 1实物类商品
+	PassbackParams     string        `json:"passback_params,omitempty"`      //公用回传参数
+	EnablePayChannels  string        `json:"enable_pay_channels,omitempty"`  //可用渠道
+	DisablePayChannels string        `json:"disable_pay_channels,omitempty"` //禁用渠道
+	StoreId            string        `json:"store_id,omitempty"`             //商户门店编号
+	MerchantOrderNo    string        `json:"merchant_order_no,omitempty"`    //商户原始订单号
+	ExtendParams       *ExtendParams `json:"extend_params,omitempty"`        //业务扩展参数
+	SettleInfo         *SettleInfo   `json:"settle_info,omitempty"`          //描述结算信息
+}
+
+//App支付 返回客户端SDK调起支付所需的订单字符串，不发起请求
+func (alipay *Alipay) AppPay(bizContent TradeAppPayParams) (string, error) {
+	if bizContent.OutTradeNo == "" {
+		return "", errors.New("outTradeNo未填写")
+	}
+	if bizContent.TotalAmount == "" {
+		return "", errors.New("totalAmount未填写")
+	}
+	if bizContent.Subject == "" {
+		return "", errors.New("subject未填写")
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodeQuickMsecurityPay
+	}
+	params, err := alipay.BuildQuery(MethodTradeAppPay, bizContent)
+	if err != nil {
+		return "", err
+	}
+	return params.Encode(), nil
+}
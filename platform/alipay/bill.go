@@ -0,0 +1,321 @@
+package alipay
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"github.com/gmdance/pay/utils"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+//查询对账单下载地址
+type BillDownloadURLQueryParams struct {
+	BillType string `json:"bill_type"`      //账单类型 trade/signcustomer 必填
+	BillDate string `json:"bill_date"`      //账单时间 日账单yyyy-MM-dd 月账单yyyy-MM 必填
+	Smid     string `json:"smid,omitempty"` //二级商户smid
+}
+
+type BillDownloadURLQueryResult struct {
+	Result
+	BillDownloadUrl string `json:"bill_download_url"` //账单下载地址链接 30秒有效
+}
+
+func (alipay *Alipay) BillDownloadURLQuery(bizContent BillDownloadURLQueryParams) (*BillDownloadURLQueryResult, string, error) {
+	if bizContent.BillType == "" {
+		return nil, "", errors.New("billType未填写")
+	}
+	if bizContent.BillDate == "" {
+		return nil, "", errors.New("billDate未填写")
+	}
+	var result BillDownloadURLQueryResult
+	data, err := alipay.Request(MethodBillDownloadURLQuery, bizContent, &result)
+	return &result, data, err
+}
+
+//查询账单下载地址并下载到filePath
+func (alipay *Alipay) DownloadBill(bizContent BillDownloadURLQueryParams, filePath string) error {
+	result, _, err := alipay.BillDownloadURLQuery(bizContent)
+	if err != nil {
+		return err
+	}
+	if result.BillDownloadUrl == "" {
+		return errors.New("账单下载地址为空:" + result.SubMsg)
+	}
+	return DownloadBillFile(result.BillDownloadUrl, filePath)
+}
+
+//下载账单压缩包到filePath
+func DownloadBillFile(billURL, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	err = utils.HttpDownload(billURL, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+//交易账单（trade）业务明细
+type BillTradeDetail struct {
+	TradeNo           string `bill:"支付宝交易号"`
+	OutTradeNo        string `bill:"商户订单号"`
+	BizType           string `bill:"业务类型"`
+	Subject           string `bill:"商品名称"`
+	GmtCreate         string `bill:"创建时间"`
+	GmtFinish         string `bill:"完成时间"`
+	StoreId           string `bill:"门店编号"`
+	StoreName         string `bill:"门店名称"`
+	Operator          string `bill:"操作员"`
+	TerminalId        string `bill:"终端号"`
+	BuyerAccount      string `bill:"对方账户"`
+	TotalAmount       string `bill:"订单金额（元）"`
+	ReceiptAmount     string `bill:"商家实收（元）"`
+	RedPacketAmount   string `bill:"支付宝红包（元）"`
+	PointAmount       string `bill:"集分宝（元）"`
+	DiscountAmount    string `bill:"支付宝优惠（元）"`
+	MdiscountAmount   string `bill:"商家优惠（元）"`
+	VoucherAmount     string `bill:"券核销金额（元）"`
+	VoucherName       string `bill:"券名称"`
+	MerchantRedPacket string `bill:"商家红包消费金额（元）"`
+	CardAmount        string `bill:"卡消费金额（元）"`
+	OutRequestNo      string `bill:"退款批次号/请求号"`
+	ServiceFee        string `bill:"服务费（元）"`
+	RoyaltyAmount     string `bill:"分润（元）"`
+	Remark            string `bill:"备注"`
+}
+
+//交易账单（trade）业务汇总
+type BillTradeSummary struct {
+	StoreId         string `bill:"门店编号"`
+	StoreName       string `bill:"门店名称"`
+	TradeCount      string `bill:"交易订单总笔数"`
+	RefundCount     string `bill:"退款订单总笔数"`
+	TotalAmount     string `bill:"订单金额（元）"`
+	ReceiptAmount   string `bill:"商家实收（元）"`
+	DiscountAmount  string `bill:"支付宝优惠（元）"`
+	MdiscountAmount string `bill:"商家优惠（元）"`
+	CardAmount      string `bill:"卡消费金额（元）"`
+	ServiceFee      string `bill:"服务费（元）"`
+	RoyaltyAmount   string `bill:"分润（元）"`
+	NetAmount       string `bill:"实收净额（元）"`
+}
+
+//资金账单（signcustomer）账务明细
+type BillAccountDetail struct {
+	AccountLogId  string `bill:"账务流水号"`
+	BizNo         string `bill:"业务流水号"`
+	OutTradeNo    string `bill:"商户订单号"`
+	Subject       string `bill:"商品名称"`
+	TransDate     string `bill:"发生时间"`
+	OtherAccount  string `bill:"对方账号"`
+	IncomeAmount  string `bill:"收入金额（+元）"`
+	ExpenseAmount string `bill:"支出金额（-元）"`
+	Balance       string `bill:"账户余额（元）"`
+	TradeChannel  string `bill:"交易渠道"`
+	BizType       string `bill:"业务类型"`
+	Remark        string `bill:"备注"`
+}
+
+//资金账单（signcustomer）账务汇总
+type BillAccountSummary struct {
+	BizType       string `bill:"业务类型"`
+	IncomeCount   string `bill:"收入笔数"`
+	IncomeAmount  string `bill:"收入金额（+元）"`
+	ExpenseCount  string `bill:"支出笔数"`
+	ExpenseAmount string `bill:"支出金额（-元）"`
+	TotalAmount   string `bill:"合计（元）"`
+}
+
+//交易账单汇总数据 Rows为各行明细，Total为合计行
+type BillTradeSummaryResult struct {
+	Rows  []*BillTradeSummary
+	Total *BillTradeSummary
+}
+
+//资金账单汇总数据 Rows为各行明细，Total为合计行
+type BillAccountSummaryResult struct {
+	Rows  []*BillAccountSummary
+	Total *BillAccountSummary
+}
+
+//账单压缩包读取，明细按行回调，不会一次性读入内存
+type BillReader struct {
+	file    *os.File
+	detail  *zip.File
+	summary *zip.File
+}
+
+//打开下载的账单压缩包
+func OpenBill(filePath string) (*BillReader, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	reader, err := NewBillReader(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	reader.file = file
+	return reader, nil
+}
+
+func NewBillReader(r io.ReaderAt, size int64) (*BillReader, error) {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	reader := &BillReader{}
+	for _, f := range zipReader.File {
+		name := f.Name
+		if !utf8.ValidString(name) {
+			if decoded, err := simplifiedchinese.GBK.NewDecoder().String(name); err == nil {
+				name = decoded
+			}
+		}
+		if !strings.HasSuffix(strings.ToLower(name), ".csv") {
+			continue
+		}
+		if strings.Contains(name, "汇总") {
+			reader.summary = f
+		} else {
+			reader.detail = f
+		}
+	}
+	if reader.detail == nil {
+		return nil, errors.New("账单明细文件不存在")
+	}
+	return reader, nil
+}
+
+func (reader *BillReader) Close() error {
+	if reader.file != nil {
+		return reader.file.Close()
+	}
+	return nil
+}
+
+//逐行读取交易账单明细
+func (reader *BillReader) EachTradeDetail(fn func(detail *BillTradeDetail) error) error {
+	return eachBillRecord(reader.detail, func(header, record []string) error {
+		var detail BillTradeDetail
+		fillBillRecord(&detail, header, record)
+		return fn(&detail)
+	})
+}
+
+//逐行读取资金账单明细
+func (reader *BillReader) EachAccountDetail(fn func(detail *BillAccountDetail) error) error {
+	return eachBillRecord(reader.detail, func(header, record []string) error {
+		var detail BillAccountDetail
+		fillBillRecord(&detail, header, record)
+		return fn(&detail)
+	})
+}
+
+//读取交易账单汇总
+func (reader *BillReader) TradeSummary() (*BillTradeSummaryResult, error) {
+	summary := &BillTradeSummaryResult{}
+	err := reader.readSummary(func(header, record []string, total bool) {
+		row := &BillTradeSummary{}
+		fillBillRecord(row, header, record)
+		if total {
+			summary.Total = row
+		} else {
+			summary.Rows = append(summary.Rows, row)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+//读取资金账单汇总
+func (reader *BillReader) AccountSummary() (*BillAccountSummaryResult, error) {
+	summary := &BillAccountSummaryResult{}
+	err := reader.readSummary(func(header, record []string, total bool) {
+		row := &BillAccountSummary{}
+		fillBillRecord(row, header, record)
+		if total {
+			summary.Total = row
+		} else {
+			summary.Rows = append(summary.Rows, row)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+//按行回调汇总文件，total表示是否为合计行
+func (reader *BillReader) readSummary(fn func(header, record []string, total bool)) error {
+	if reader.summary == nil {
+		return errors.New("账单汇总文件不存在")
+	}
+	return eachBillRecord(reader.summary, func(header, record []string) error {
+		fn(header, record, record[0] == "合计")
+		return nil
+	})
+}
+
+//读取GBK编码的csv，跳过#开头的说明行，第一行非说明行为表头
+func eachBillRecord(f *zip.File, fn func(header, record []string) error) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	csvReader := csv.NewReader(simplifiedchinese.GBK.NewDecoder().Reader(rc))
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	var header []string
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		if header == nil {
+			header = record
+			continue
+		}
+		if err = fn(header, record); err != nil {
+			return err
+		}
+	}
+}
+
+//按表头名称填充带bill标签的字段
+func fillBillRecord(v interface{}, header, record []string) {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name := rt.Field(i).Tag.Get("bill")
+		for j, column := range header {
+			if column == name && j < len(record) {
+				rv.Field(i).SetString(record[j])
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//统一收单交易关闭
+type TradeCloseParams struct {
+	OutTradeNo string `json:"out_trade_no,omitempty"` //商户订单号 与trade_no二选一
+	TradeNo    string `json:"trade_no,omitempty"`     //支付宝交易号 与out_trade_no二选一
+	OperatorId string `json:"operator_id,omitempty"`  //商户操作员编号
+}
+
+type TradeCloseResult struct {
+	Result
+	TradeNo    string `json:"trade_no"`     //支付宝交易号
+	OutTradeNo string `json:"out_trade_no"` //商户订单号
+}
+
+func (alipay *Alipay) TradeClose(bizContent TradeCloseParams) (*TradeCloseResult, string, error) {
+	if bizContent.OutTradeNo == "" && bizContent.TradeNo == "" {
+		return nil, "", errors.New("outTradeNo和tradeNo必须填写一项")
+	}
+	var result TradeCloseResult
+	data, err := alipay.Request(MethodTradeClose, bizContent, &result)
+	return &result, data, err
+}
+
+//统一收单交易撤销
+type TradeCancelParams struct {
+	OutTradeNo string `json:"out_trade_no,omitempty"` //商户订单号 与trade_no二选一
+	TradeNo    string `json:"trade_no,omitempty"`     //支付宝交易号 与out_trade_no二选一
+}
+
+type TradeCancelResult struct {
+	Result
+	TradeNo            string `json:"trade_no"`             //支付宝交易号
+	OutTradeNo         string `json:"out_trade_no"`         //商户订单号
+	RetryFlag          string `json:"retry_flag"`           //是否需要重试 Y/N
+	Action             string `json:"action"`               //本次撤销触发的交易动作 close/refund
+	GmtRefundPay       string `json:"gmt_refund_pay"`       //撤销触发退款时的退款时间
+	RefundSettlementId string `json:"refund_settlement_id"` //返回的退款清算编号
+}
+
+//是否需要重试撤销
+func (result *TradeCancelResult) NeedRetry() bool {
+	return result.RetryFlag == "Y" || (&AlipayError{Code: result.Code, SubCode: result.SubCode}).IsRetryable()
+}
+
+func (alipay *Alipay) TradeCancel(bizContent TradeCancelParams) (*TradeCancelResult, string, error) {
+	if bizContent.OutTradeNo == "" && bizContent.TradeNo == "" {
+		return nil, "", errors.New("outTradeNo和tradeNo必须填写一项")
+	}
+	var result TradeCancelResult
+	data, err := alipay.Request(MethodTradeCancel, bizContent, &result)
+	return &result, data, err
+}
+
+//撤销交易直到支付宝返回最终结果，最多重试maxRetry次，每次间隔interval
+//网络异常等未拿到支付宝业务结果的错误同样重试，ctx取消时停止重试并返回ctx.Err()
+func (alipay *Alipay) TradeCancelUntilDone(ctx context.Context, bizContent TradeCancelParams, maxRetry int, interval time.Duration) (*TradeCancelResult, string, error) {
+	client := alipay.WithContext(ctx)
+	for i := 0; ; i++ {
+		result, data, err := client.TradeCancel(bizContent)
+		if result == nil {
+			return result, data, err
+		}
+		_, isAlipayError := AsAlipayError(err)
+		if !result.NeedRetry() && (err == nil || isAlipayError) {
+			return result, data, err
+		}
+		if i >= maxRetry {
+			if err == nil {
+				err = errors.New("撤销交易重试次数已用完")
+			}
+			return result, data, err
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, data, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
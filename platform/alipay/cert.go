@@ -0,0 +1,176 @@
+package alipay
+
+import (
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+//公钥证书模式下载支付宝公钥证书
+type AlipayCertDownloadParams struct {
+	AlipayCertSn string `json:"alipay_cert_sn"` //支付宝公钥证书序列号 必填
+}
+
+type AlipayCertDownloadResult struct {
+	Result
+	AlipayCertContent string `json:"alipay_cert_content"` //Base64编码的支付宝公钥证书
+}
+
+//是否为公钥证书模式
+func (alipay *Alipay) IsCertMode() bool {
+	return alipay.conf.AppPublicCert != ""
+}
+
+//加载支付宝公钥证书，并作为后续验签默认使用的证书
+func (alipay *Alipay) LoadAlipayPublicCert(cert string) error {
+	certs, err := ParseCerts([]byte(cert))
+	if err != nil {
+		return err
+	}
+	if alipay.conf.AlipayRootCert != "" {
+		if err = VerifyCertChain(certs, alipay.conf.AlipayRootCert); err != nil {
+			return err
+		}
+	}
+	key, ok := certs[0].PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("alipay public cert is not rsa")
+	}
+	sn := GetCertSN(certs[0])
+	alipay.certs.Lock()
+	alipay.certs.keys[sn] = key
+	alipay.certs.currentSN = sn
+	alipay.certs.Unlock()
+	return nil
+}
+
+//下载指定序列号的支付宝公钥证书，校验证书链后加载
+func (alipay *Alipay) DownloadAlipayCert(certSN string) error {
+	var result AlipayCertDownloadResult
+	_, err := alipay.request(MethodAlipayCertDownload, AlipayCertDownloadParams{AlipayCertSn: certSN}, nil, &result, false)
+	if err != nil {
+		return err
+	}
+	content, err := base64.StdEncoding.DecodeString(result.AlipayCertContent)
+	if err != nil {
+		return err
+	}
+	if alipay.conf.AlipayRootCert == "" {
+		return errors.New("alipayRootCert未配置，无法校验下载的证书")
+	}
+	certs, err := ParseCerts(content)
+	if err != nil {
+		return err
+	}
+	if GetCertSN(certs[0]) != certSN {
+		return errors.New("下载的支付宝公钥证书序列号不匹配")
+	}
+	return alipay.LoadAlipayPublicCert(string(content))
+}
+
+//获取验签公钥，certSN为空时使用当前证书，证书不存在时自动下载
+func (alipay *Alipay) certPublicKey(certSN string) (*rsa.PublicKey, error) {
+	alipay.certs.RLock()
+	if certSN == "" {
+		certSN = alipay.certs.currentSN
+	}
+	key, ok := alipay.certs.keys[certSN]
+	alipay.certs.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if certSN == "" {
+		return nil, errors.New("alipayPublicCert未配置")
+	}
+	if err := alipay.DownloadAlipayCert(certSN); err != nil {
+		return nil, err
+	}
+	return alipay.certPublicKey(certSN)
+}
+
+//解析PEM格式的证书，支持多个证书拼接
+func ParseCerts(raw []byte) ([]*x509.Certificate, error) {
+	return parseCerts(raw, false)
+}
+
+//解析支付宝根证书 根证书中包含Go无法解析的SM2证书，跳过解析失败的证书
+func parseRootCerts(raw []byte) ([]*x509.Certificate, error) {
+	return parseCerts(raw, true)
+}
+
+func parseCerts(raw []byte, skipInvalid bool) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, raw = pem.Decode(raw)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			if skipInvalid {
+				continue
+			}
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("cert error")
+	}
+	return certs, nil
+}
+
+//证书序列号 md5(签发者DN+序列号)
+func GetCertSN(cert *x509.Certificate) string {
+	sum := md5.Sum([]byte(cert.Issuer.String() + cert.SerialNumber.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+//根证书序列号 只计算RSA签名算法的证书，以下划线拼接
+func GetRootCertSN(raw []byte) (string, error) {
+	certs, err := parseRootCerts(raw)
+	if err != nil {
+		return "", err
+	}
+	var sns []string
+	for _, cert := range certs {
+		switch cert.SignatureAlgorithm {
+		case x509.SHA1WithRSA, x509.SHA256WithRSA:
+			sns = append(sns, GetCertSN(cert))
+		}
+	}
+	if len(sns) == 0 {
+		return "", errors.New("alipayRootCert中没有RSA签名的证书")
+	}
+	return strings.Join(sns, "_"), nil
+}
+
+//使用支付宝根证书校验证书链
+func VerifyCertChain(certs []*x509.Certificate, rootCert string) error {
+	roots, err := parseRootCerts([]byte(rootCert))
+	if err != nil {
+		return err
+	}
+	opts := x509.VerifyOptions{
+		Roots:         x509.NewCertPool(),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	for _, cert := range roots {
+		opts.Roots.AddCert(cert)
+	}
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err = certs[0].Verify(opts)
+	return err
+}
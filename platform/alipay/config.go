@@ -1,11 +1,22 @@
 package alipay
 
 type Config struct {
-	AppID           string `json:"app_id"`
-	Partner         string `json:"partner"`
-	SignType        string `json:"sign_type"`
-	AlipayPublicKey string `json:"alipay_public_key"`
-	AppPrivateKey   string `json:"app_private_key"`
-	PayNotifyURL    string `json:"pay_notify_url"`
-	RefundNotifyURL string `json:"refund_notify_url"`
+	AppID                 string `json:"app_id"`
+	Env                   string `json:"env"`          //运行环境 production/sandbox 默认production
+	Gateway               string `json:"gateway"`      //自定义网关地址 配置后忽略env
+	Partner               string `json:"partner"`      //旧版mapi网关合作伙伴身份ID
+	MD5Key                string `json:"md5_key"`      //旧版mapi网关MD5密钥
+	MapiGateway           string `json:"mapi_gateway"` //自定义旧版mapi网关地址
+	SignType              string `json:"sign_type"`
+	AlipayPublicKey       string `json:"alipay_public_key"`
+	AppPrivateKey         string `json:"app_private_key"`
+	AppPrivateKeyFile     string `json:"app_private_key_file"`     //应用私钥文件路径 未配置appPrivateKey时使用
+	AppPrivateKeyPassword string `json:"app_private_key_password"` //加密私钥的密码
+	AppPublicCert         string `json:"app_public_cert"`          //应用公钥证书 配置后使用公钥证书模式
+	AlipayPublicCert      string `json:"alipay_public_cert"`       //支付宝公钥证书
+	AlipayRootCert        string `json:"alipay_root_cert"`         //支付宝根证书
+	EncryptKey            string `json:"encrypt_key"`              //接口内容加密AES密钥 配置后加密biz_content
+	PayNotifyURL          string `json:"pay_notify_url"`
+	RefundNotifyURL       string `json:"refund_notify_url"`
+	AgreementNotifyURL    string `json:"agreement_notify_url"` //代扣协议签约、解约异步通知地址
 }
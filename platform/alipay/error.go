@@ -0,0 +1,52 @@
+package alipay
+
+import "fmt"
+
+//支付宝业务错误 网关返回非10000的code或error_response时返回
+type AlipayError struct {
+	Code    string `json:"code"`
+	Msg     string `json:"msg"`
+	SubCode string `json:"sub_code"`
+	SubMsg  string `json:"sub_msg"`
+}
+
+func (e *AlipayError) Error() string {
+	if e.SubCode == "" {
+		return fmt.Sprintf("支付宝业务失败:%s(%s)", e.Msg, e.Code)
+	}
+	return fmt.Sprintf("支付宝业务失败:%s(%s) %s(%s)", e.Msg, e.Code, e.SubMsg, e.SubCode)
+}
+
+//系统繁忙或结果未知，可以使用相同参数重试
+func (e *AlipayError) IsRetryable() bool {
+	switch e.SubCode {
+	case "ACQ.SYSTEM_ERROR", "aop.ACQ.SYSTEM_ERROR", "isp.unknow-error", "SYSTEM_ERROR":
+		return true
+	}
+	return e.Code == CodeSystemError
+}
+
+//等待用户付款
+func (e *AlipayError) IsWaitUserPay() bool {
+	return e.Code == CodeWaitUserPay
+}
+
+//交易不存在
+func (e *AlipayError) IsTradeNotExist() bool {
+	return e.SubCode == "ACQ.TRADE_NOT_EXIST"
+}
+
+//买家或付款方余额不足
+func (e *AlipayError) IsInsufficientBalance() bool {
+	switch e.SubCode {
+	case "ACQ.BUYER_BALANCE_NOT_ENOUGH", "PAYER_BALANCE_NOT_ENOUGH", "BALANCE_IS_NOT_ENOUGH", "ACQ.BUYER_BANKCARD_BALANCE_NOT_ENOUGH":
+		return true
+	}
+	return false
+}
+
+//判断err是否为支付宝业务错误
+func AsAlipayError(err error) (*AlipayError, bool) {
+	e, ok := err.(*AlipayError)
+	return e, ok
+}
@@ -0,0 +1,201 @@
+package alipay
+
+import "errors"
+
+//资金授权冻结（App）与资金授权发码（voucher）
+type FundAuthOrderFreezeParams struct {
+	OutOrderNo         string `json:"out_order_no"`                   //商户授权资金订单号 必填
+	OutRequestNo       string `json:"out_request_no"`                 //商户本次资金操作的请求流水号 必填
+	OrderTitle         string `json:"order_title"`                    //业务订单的简单描述 必填
+	Amount             string `json:"amount"`                         //需要冻结的金额 必填
+	ProductCode        string `json:"product_code"`                   //销售产品码 App冻结PRE_AUTH_ONLINE 发码PRE_AUTH
+	PayeeLogonId       string `json:"payee_logon_id,omitempty"`       //收款方支付宝账号
+	PayeeUserId        string `json:"payee_user_id,omitempty"`        //收款方的支付宝唯一用户号
+	PayTimeout         string `json:"pay_timeout,omitempty"`          //该笔订单允许的最晚付款时间
+	TimeExpress        string `json:"time_express,omitempty"`         //预授权订单相对超时时间
+	ExtraParam         string `json:"extra_param,omitempty"`          //业务扩展参数 如{"category":"RENT_DIGITAL"}
+	SceneCode          string `json:"scene_code,omitempty"`           //预授权业务信息
+	TransCurrency      string `json:"trans_currency,omitempty"`       //标价币种
+	SettleCurrency     string `json:"settle_currency,omitempty"`      //商户指定的结算币种
+	EnablePayChannels  string `json:"enable_pay_channels,omitempty"`  //商户可用该参数指定用户可使用的支付渠道
+	DisablePayChannels string `json:"disable_pay_channels,omitempty"` //商户可用该参数禁用支付渠道
+	DepositProductMode string `json:"deposit_product_mode,omitempty"` //免押受理台模式 POSTPAY/DEPOSIT_ONLY
+	CodeType           string `json:"code_type,omitempty"`            //发码的码类型 发码时使用 如qrCode
+}
+
+func (params FundAuthOrderFreezeParams) check() error {
+	if params.OutOrderNo == "" {
+		return errors.New("outOrderNo未填写")
+	}
+	if params.OutRequestNo == "" {
+		return errors.New("outRequestNo未填写")
+	}
+	if params.OrderTitle == "" {
+		return errors.New("orderTitle未填写")
+	}
+	if params.Amount == "" {
+		return errors.New("amount未填写")
+	}
+	return nil
+}
+
+//App资金授权冻结 返回客户端SDK调起冻结所需的订单字符串，不发起请求
+func (alipay *Alipay) FundAuthOrderAppFreeze(bizContent FundAuthOrderFreezeParams) (string, error) {
+	if err := bizContent.check(); err != nil {
+		return "", err
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodePreAuthOnline
+	}
+	params, err := alipay.BuildQuery(MethodFundAuthOrderAppFreeze, bizContent)
+	if err != nil {
+		return "", err
+	}
+	return params.Encode(), nil
+}
+
+type FundAuthOrderVoucherCreateResult struct {
+	Result
+	OutOrderNo   string `json:"out_order_no"`   //商户的授权资金订单号
+	OutRequestNo string `json:"out_request_no"` //商户本次资金操作的请求流水号
+	CodeType     string `json:"code_type"`      //码类型
+	CodeValue    string `json:"code_value"`     //当前发码请求生成的二维码码串
+	CodeUrl      string `json:"code_url"`       //生成的二维码图片地址
+}
+
+//资金授权发码 生成二维码供用户扫码冻结
+func (alipay *Alipay) FundAuthOrderVoucherCreate(bizContent FundAuthOrderFreezeParams) (*FundAuthOrderVoucherCreateResult, string, error) {
+	if err := bizContent.check(); err != nil {
+		return nil, "", err
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodePreAuth
+	}
+	var result FundAuthOrderVoucherCreateResult
+	data, err := alipay.Request(MethodFundAuthOrderVoucherCreate, bizContent, &result)
+	return &result, data, err
+}
+
+//资金授权解冻
+type FundAuthOrderUnfreezeParams struct {
+	AuthNo       string `json:"auth_no"`               //支付宝资金授权订单号 必填
+	OutRequestNo string `json:"out_request_no"`        //商户本次资金操作的请求流水号 必填
+	Amount       string `json:"amount"`                //本次操作解冻的金额 必填
+	Remark       string `json:"remark"`                //商户对本次解冻操作的附言描述 必填
+	ExtraParam   string `json:"extra_param,omitempty"` //解冻扩展信息
+}
+
+type FundAuthOrderUnfreezeResult struct {
+	Result
+	AuthNo       string `json:"auth_no"`        //支付宝资金授权订单号
+	OutOrderNo   string `json:"out_order_no"`   //商户的授权资金订单号
+	OperationId  string `json:"operation_id"`   //支付宝资金操作流水号
+	OutRequestNo string `json:"out_request_no"` //商户本次资金操作的请求流水号
+	Amount       string `json:"amount"`         //本次解冻操作中信用解冻和自有资金解冻的总金额
+	Status       string `json:"status"`         //资金操作流水的状态 INIT/SUCCESS/CLOSED
+	GmtTrans     string `json:"gmt_trans"`      //授权资金解冻成功时间
+	CreditAmount string `json:"credit_amount"`  //本次解冻操作中信用解冻金额
+	FundAmount   string `json:"fund_amount"`    //本次解冻操作中自有资金解冻金额
+}
+
+func (alipay *Alipay) FundAuthOrderUnfreeze(bizContent FundAuthOrderUnfreezeParams) (*FundAuthOrderUnfreezeResult, string, error) {
+	if bizContent.AuthNo == "" {
+		return nil, "", errors.New("authNo未填写")
+	}
+	if bizContent.OutRequestNo == "" {
+		return nil, "", errors.New("outRequestNo未填写")
+	}
+	if bizContent.Amount == "" {
+		return nil, "", errors.New("amount未填写")
+	}
+	if bizContent.Remark == "" {
+		return nil, "", errors.New("remark未填写")
+	}
+	var result FundAuthOrderUnfreezeResult
+	data, err := alipay.Request(MethodFundAuthOrderUnfreeze, bizContent, &result)
+	return &result, data, err
+}
+
+//资金授权操作查询与撤销
+type FundAuthOperationParams struct {
+	AuthNo       string `json:"auth_no,omitempty"`        //支付宝授权资金订单号 与out_order_no二选一
+	OutOrderNo   string `json:"out_order_no,omitempty"`   //商户的授权资金订单号 与auth_no二选一
+	OperationId  string `json:"operation_id,omitempty"`   //支付宝的授权资金操作流水号 与out_request_no二选一
+	OutRequestNo string `json:"out_request_no,omitempty"` //商户的授权资金操作流水号 与operation_id二选一
+	Remark       string `json:"remark,omitempty"`         //商户对本次撤销操作的附言描述 撤销时必填
+}
+
+func (params FundAuthOperationParams) check() error {
+	if params.AuthNo == "" && params.OutOrderNo == "" {
+		return errors.New("authNo和outOrderNo必须填写一项")
+	}
+	if params.OperationId == "" && params.OutRequestNo == "" {
+		return errors.New("operationId和outRequestNo必须填写一项")
+	}
+	return nil
+}
+
+type FundAuthOperationDetailQueryResult struct {
+	Result
+	AuthNo                  string `json:"auth_no"`                    //支付宝资金授权订单号
+	OutOrderNo              string `json:"out_order_no"`               //商户的授权资金订单号
+	OrderStatus             string `json:"order_status"`               //资金授权单据状态 INIT/AUTHORIZED/FINISH/CLOSED
+	TotalFreezeAmount       string `json:"total_freeze_amount"`        //订单累计的冻结金额
+	RestAmount              string `json:"rest_amount"`                //订单总共剩余的冻结金额
+	TotalPayAmount          string `json:"total_pay_amount"`           //订单累计用于支付的金额
+	OrderTitle              string `json:"order_title"`                //业务订单的简单描述
+	PayerLogonId            string `json:"payer_logon_id"`             //付款方支付宝账号登录号
+	PayerUserId             string `json:"payer_user_id"`              //付款方支付宝账号UID
+	ExtraParam              string `json:"extra_param"`                //商户请求创建预授权订单时传入的扩展参数
+	OperationId             string `json:"operation_id"`               //支付宝资金操作流水号
+	OutRequestNo            string `json:"out_request_no"`             //商户资金操作的请求流水号
+	Amount                  string `json:"amount"`                     //该笔资金操作流水operation_id对应的操作金额
+	OperationType           string `json:"operation_type"`             //资金操作类型 FREEZE/UNFREEZE/PAY
+	Status                  string `json:"status"`                     //资金操作流水的状态 INIT/SUCCESS/CLOSED
+	Remark                  string `json:"remark"`                     //商户对本次操作的附言描述
+	GmtCreate               string `json:"gmt_create"`                 //资金授权单据操作流水创建时间
+	GmtTrans                string `json:"gmt_trans"`                  //支付宝账务处理成功时间
+	PreAuthType             string `json:"pre_auth_type"`              //预授权类型 CREDIT_AUTH为信用预授权
+	TransCurrency           string `json:"trans_currency"`             //标价币种
+	TotalFreezeCreditAmount string `json:"total_freeze_credit_amount"` //累计冻结信用金额
+	TotalFreezeFundAmount   string `json:"total_freeze_fund_amount"`   //累计冻结自有资金金额
+	TotalPayCreditAmount    string `json:"total_pay_credit_amount"`    //累计支付信用金额
+	TotalPayFundAmount      string `json:"total_pay_fund_amount"`      //累计支付自有资金金额
+	RestCreditAmount        string `json:"rest_credit_amount"`         //剩余冻结信用金额
+	RestFundAmount          string `json:"rest_fund_amount"`           //剩余冻结自有资金金额
+	CreditAmount            string `json:"credit_amount"`              //该笔资金操作流水中信用金额
+	FundAmount              string `json:"fund_amount"`                //该笔资金操作流水中自有资金金额
+}
+
+//资金授权操作查询
+func (alipay *Alipay) FundAuthOperationDetailQuery(bizContent FundAuthOperationParams) (*FundAuthOperationDetailQueryResult, string, error) {
+	if err := bizContent.check(); err != nil {
+		return nil, "", err
+	}
+	bizContent.Remark = ""
+	var result FundAuthOperationDetailQueryResult
+	data, err := alipay.Request(MethodFundAuthOperationDetailQuery, bizContent, &result)
+	return &result, data, err
+}
+
+type FundAuthOperationCancelResult struct {
+	Result
+	AuthNo       string `json:"auth_no"`        //支付宝资金授权订单号
+	OutOrderNo   string `json:"out_order_no"`   //商户的授权资金订单号
+	OperationId  string `json:"operation_id"`   //支付宝资金操作流水号
+	OutRequestNo string `json:"out_request_no"` //商户本次资金操作的请求流水号
+	Action       string `json:"action"`         //本次撤销触发的资金动作 close/unfreeze
+}
+
+//资金授权撤销 冻结结果未知或超时时调用
+func (alipay *Alipay) FundAuthOperationCancel(bizContent FundAuthOperationParams) (*FundAuthOperationCancelResult, string, error) {
+	if err := bizContent.check(); err != nil {
+		return nil, "", err
+	}
+	if bizContent.Remark == "" {
+		return nil, "", errors.New("remark未填写")
+	}
+	var result FundAuthOperationCancelResult
+	data, err := alipay.Request(MethodFundAuthOperationCancel, bizContent, &result)
+	return &result, data, err
+}
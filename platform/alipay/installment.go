@@ -0,0 +1,128 @@
+package alipay
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//花呗分期费率 单位万分之一
+var hbFqRates = map[int]int64{
+	3:  230,
+	6:  450,
+	12: 750,
+}
+
+//业务扩展参数
+type ExtendParams struct {
+	SysServiceProviderId string `json:"sys_service_provider_id,omitempty"` //系统商编号
+	SpecifiedSellerName  string `json:"specified_seller_name,omitempty"`   //特殊场景下，允许商户指定交易展示的卖家名称
+	CardType             string `json:"card_type,omitempty"`               //卡类型
+	RoyaltyFreeze        string `json:"royalty_freeze,omitempty"`          //是否进行资金冻结，用于后续分账 true/false
+	HbFqNum              string `json:"hb_fq_num,omitempty"`               //花呗分期数 3/6/12
+	HbFqSellerPercent    string `json:"hb_fq_seller_percent,omitempty"`    //卖家承担手续费比例 0用户承担/100商家承担
+}
+
+type HbFqPeriod struct {
+	Principal string //本期本金
+	Fee       string //本期用户承担的手续费
+	Amount    string //本期用户应还金额
+}
+
+type HbFqPlan struct {
+	Num           int          //分期数
+	SellerPercent int          //卖家承担手续费比例 0/100
+	Rate          string       //分期费率
+	TotalAmount   string       //订单金额
+	TotalFee      string       //总手续费
+	BuyerFee      string       //用户承担的手续费
+	SellerFee     string       //商家承担的手续费
+	Periods       []HbFqPeriod //每期还款明细
+}
+
+//花呗分期试算 总手续费按银行家舍入到分，每期本金和手续费向下取整，零头计入第一期
+func HbFqCalculate(totalAmount string, num int, sellerPercent int) (*HbFqPlan, error) {
+	rate, ok := hbFqRates[num]
+	if !ok {
+		return nil, errors.New("hbFqNum仅支持3/6/12")
+	}
+	if sellerPercent != 0 && sellerPercent != 100 {
+		return nil, errors.New("hbFqSellerPercent仅支持0/100")
+	}
+	amount, err := parseAmount(totalAmount)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, errors.New("totalAmount必须大于0")
+	}
+	totalFee := roundHalfEven(amount*rate, 10000)
+	var buyerFee, sellerFee int64
+	if sellerPercent == 100 {
+		sellerFee = totalFee
+	} else {
+		buyerFee = totalFee
+	}
+	plan := &HbFqPlan{
+		Num:           num,
+		SellerPercent: sellerPercent,
+		Rate:          strconv.FormatFloat(float64(rate)/10000, 'f', -1, 64),
+		TotalAmount:   formatAmount(amount),
+		TotalFee:      formatAmount(totalFee),
+		BuyerFee:      formatAmount(buyerFee),
+		SellerFee:     formatAmount(sellerFee),
+		Periods:       make([]HbFqPeriod, num),
+	}
+	n := int64(num)
+	for i := range plan.Periods {
+		principal, fee := amount/n, buyerFee/n
+		if i == 0 {
+			principal += amount % n
+			fee += buyerFee % n
+		}
+		plan.Periods[i] = HbFqPeriod{
+			Principal: formatAmount(principal),
+			Fee:       formatAmount(fee),
+			Amount:    formatAmount(principal + fee),
+		}
+	}
+	return plan, nil
+}
+
+//生成下单时使用的extend_params
+func (plan *HbFqPlan) ExtendParams() *ExtendParams {
+	return &ExtendParams{
+		HbFqNum:           strconv.Itoa(plan.Num),
+		HbFqSellerPercent: strconv.Itoa(plan.SellerPercent),
+	}
+}
+
+func roundHalfEven(x, y int64) int64 {
+	q, r := x/y, x%y
+	if r*2 > y || (r*2 == y && q%2 == 1) {
+		q++
+	}
+	return q
+}
+
+//元转分
+func parseAmount(amount string) (int64, error) {
+	yuan, cent := amount, ""
+	if i := strings.IndexByte(amount, '.'); i >= 0 {
+		yuan, cent = amount[:i], amount[i+1:]
+	}
+	if yuan == "" || len(cent) > 2 || strings.ContainsAny(yuan+cent, "+-") {
+		return 0, errors.New("金额格式错误: " + amount)
+	}
+	cent += strings.Repeat("0", 2-len(cent))
+	n, err := strconv.ParseInt(yuan+cent, 10, 64)
+	if err != nil {
+		return 0, errors.New("金额格式错误: " + amount)
+	}
+	return n, nil
+}
+
+//分转元
+func formatAmount(cents int64) string {
+	return strconv.FormatInt(cents/100, 10) + "." + strconv.FormatInt(cents%100/10, 10) + strconv.FormatInt(cents%10, 10)
+}
@@ -0,0 +1,137 @@
+package alipay
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+//换取应用授权令牌
+type OpenAuthTokenAppParams struct {
+	GrantType    string `json:"grant_type"`              //授权方式 authorization_code/refresh_token 必填
+	Code         string `json:"code,omitempty"`          //应用授权码 grant_type为authorization_code时必填
+	RefreshToken string `json:"refresh_token,omitempty"` //刷新令牌 grant_type为refresh_token时必填
+}
+
+type OpenAuthTokenAppResult struct {
+	Result
+	UserId          string      `json:"user_id"`           //授权商户的user_id
+	AuthAppId       string      `json:"auth_app_id"`       //授权商户的appid
+	AppAuthToken    string      `json:"app_auth_token"`    //应用授权令牌
+	AppRefreshToken string      `json:"app_refresh_token"` //刷新令牌
+	ExpiresIn       json.Number `json:"expires_in"`        //该令牌的有效期，单位秒
+	ReExpiresIn     json.Number `json:"re_expires_in"`     //刷新令牌的有效期，单位秒
+}
+
+//转换为令牌存储使用的结构
+func (result *OpenAuthTokenAppResult) Token() *AppAuthToken {
+	now := time.Now()
+	expiresIn, _ := result.ExpiresIn.Int64()
+	reExpiresIn, _ := result.ReExpiresIn.Int64()
+	return &AppAuthToken{
+		UserId:          result.UserId,
+		AuthAppId:       result.AuthAppId,
+		AppAuthToken:    result.AppAuthToken,
+		AppRefreshToken: result.AppRefreshToken,
+		ExpiresAt:       now.Add(time.Duration(expiresIn) * time.Second),
+		ReExpiresAt:     now.Add(time.Duration(reExpiresIn) * time.Second),
+	}
+}
+
+//使用应用授权码换取应用授权令牌
+func (alipay *Alipay) OpenAuthTokenApp(code string) (*OpenAuthTokenAppResult, string, error) {
+	if code == "" {
+		return nil, "", errors.New("code未填写")
+	}
+	return alipay.openAuthTokenApp(OpenAuthTokenAppParams{GrantType: GrantTypeAuthorizationCode, Code: code})
+}
+
+//使用刷新令牌刷新应用授权令牌
+func (alipay *Alipay) OpenAuthTokenAppRefresh(refreshToken string) (*OpenAuthTokenAppResult, string, error) {
+	if refreshToken == "" {
+		return nil, "", errors.New("refreshToken未填写")
+	}
+	return alipay.openAuthTokenApp(OpenAuthTokenAppParams{GrantType: GrantTypeRefreshToken, RefreshToken: refreshToken})
+}
+
+func (alipay *Alipay) openAuthTokenApp(bizContent OpenAuthTokenAppParams) (*OpenAuthTokenAppResult, string, error) {
+	var result OpenAuthTokenAppResult
+	data, err := alipay.WithAppAuthToken("").Request(MethodOpenAuthTokenApp, bizContent, &result)
+	return &result, data, err
+}
+
+//返回代指定商户调用的客户端，与原客户端共享密钥与证书，所有请求都会带上app_auth_token
+func (alipay *Alipay) WithAppAuthToken(appAuthToken string) *Alipay {
+	client := *alipay
+	client.appAuthToken = appAuthToken
+	return &client
+}
+
+//商户授权令牌
+type AppAuthToken struct {
+	UserId          string    `json:"user_id"`           //授权商户的user_id
+	AuthAppId       string    `json:"auth_app_id"`       //授权商户的appid
+	AppAuthToken    string    `json:"app_auth_token"`    //应用授权令牌
+	AppRefreshToken string    `json:"app_refresh_token"` //刷新令牌
+	ExpiresAt       time.Time `json:"expires_at"`        //应用授权令牌过期时间
+	ReExpiresAt     time.Time `json:"re_expires_at"`     //刷新令牌过期时间
+}
+
+//商户授权令牌存储 按授权商户的user_id读写
+//ForMerchant不做跨调用方的同步，多个调用方同时发现令牌即将过期时会各自刷新并Save，需要避免重复刷新时由实现方自行加锁
+type AppAuthTokenStore interface {
+	Get(userId string) (*AppAuthToken, error)
+	Save(token *AppAuthToken) error
+}
+
+//内存中的令牌存储，仅适用于单进程
+type MemoryAppAuthTokenStore struct {
+	lock   sync.RWMutex
+	tokens map[string]*AppAuthToken
+}
+
+func NewMemoryAppAuthTokenStore() *MemoryAppAuthTokenStore {
+	return &MemoryAppAuthTokenStore{tokens: make(map[string]*AppAuthToken)}
+}
+
+func (store *MemoryAppAuthTokenStore) Get(userId string) (*AppAuthToken, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+	token, ok := store.tokens[userId]
+	if !ok {
+		return nil, errors.New("商户未授权:" + userId)
+	}
+	return token, nil
+}
+
+func (store *MemoryAppAuthTokenStore) Save(token *AppAuthToken) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+	store.tokens[token.UserId] = token
+	return nil
+}
+
+//从存储中取出商户令牌，令牌将在一天内过期时自动刷新并保存，返回代该商户调用的客户端
+func (alipay *Alipay) ForMerchant(store AppAuthTokenStore, userId string) (*Alipay, error) {
+	token, err := store.Get(userId)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Add(24 * time.Hour).After(token.ExpiresAt) {
+		if time.Now().After(token.ReExpiresAt) {
+			return nil, errors.New("商户授权已过期，需要重新授权:" + userId)
+		}
+		result, _, err := alipay.OpenAuthTokenAppRefresh(token.AppRefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		token = result.Token()
+		//刷新响应中的user_id可能缺失，按查询时的userId保存
+		token.UserId = userId
+		if err = store.Save(token); err != nil {
+			return nil, err
+		}
+	}
+	return alipay.WithAppAuthToken(token.AppAuthToken), nil
+}
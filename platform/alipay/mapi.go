@@ -0,0 +1,435 @@
+package alipay
+
+import (
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/gmdance/pay/utils"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//旧版mapi网关客户端 使用partner和MD5密钥或RSA密钥签名
+type Mapi struct {
+	conf       Config
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+}
+
+//signType为空时使用MD5，RSA/RSA2时使用appPrivateKey签名、alipayPublicKey验签
+func NewMapi(conf Config) (*Mapi, error) {
+	if conf.Partner == "" {
+		return nil, errors.New("partner未配置")
+	}
+	if err := checkEnv(conf.Env); err != nil {
+		return nil, err
+	}
+	if conf.SignType == "" {
+		conf.SignType = SignTypeMD5
+	}
+	mapi := &Mapi{conf: conf}
+	if conf.SignType == SignTypeMD5 {
+		if conf.MD5Key == "" {
+			return nil, errors.New("md5Key未配置")
+		}
+		return mapi, nil
+	}
+	var err error
+	if conf.AppPrivateKey == "" && conf.AppPrivateKeyFile != "" {
+		mapi.privateKey, err = LoadPrivateKeyFile(conf.AppPrivateKeyFile, conf.AppPrivateKeyPassword)
+	} else {
+		mapi.privateKey, err = ParsePrivateKeyWithPassword(FormatPrivateKey(conf.AppPrivateKey), conf.AppPrivateKeyPassword)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if conf.AlipayPublicKey != "" {
+		if mapi.publicKey, err = ParsePublicKey(FormatPublicKey(conf.AlipayPublicKey)); err != nil {
+			return nil, err
+		}
+	}
+	return mapi, nil
+}
+
+func (mapi *Mapi) Gateway() string {
+	if mapi.conf.MapiGateway != "" {
+		return mapi.conf.MapiGateway
+	}
+	if mapi.conf.Env == EnvSandbox {
+		return MapiSandboxHost
+	}
+	return MapiHost
+}
+
+//旧版网关业务错误 is_success=F时返回
+type MapiError struct {
+	Code string
+}
+
+func (e *MapiError) Error() string {
+	return "支付宝业务失败:" + e.Code
+}
+
+//待签名内容 不含sign和sign_type
+func mapiSignContent(params map[string]string) []byte {
+	content := make(map[string]string, len(params))
+	for k, v := range params {
+		if k == "sign" || k == "sign_type" {
+			continue
+		}
+		content[k] = v
+	}
+	return getSignContent(content)
+}
+
+func (mapi *Mapi) Sign(params map[string]string) (string, error) {
+	if mapi.conf.SignType == SignTypeMD5 {
+		sum := md5.Sum(append(mapiSignContent(params), mapi.conf.MD5Key...))
+		return hex.EncodeToString(sum[:]), nil
+	}
+	return RSASign(mapiSignContent(params), mapi.conf.SignType, mapi.privateKey)
+}
+
+func (mapi *Mapi) VerifySign(params map[string]string) error {
+	sign := params["sign"]
+	if sign == "" {
+		return errors.New("sign为空")
+	}
+	signType := params["sign_type"]
+	if signType == "" {
+		signType = mapi.conf.SignType
+	}
+	if signType != mapi.conf.SignType {
+		return errors.New("signType不匹配: " + signType)
+	}
+	if signType == SignTypeMD5 {
+		expected, _ := mapi.Sign(params)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(sign))) != 1 {
+			return errors.New("签名验证失败")
+		}
+		return nil
+	}
+	if mapi.publicKey == nil {
+		return errors.New("alipayPublicKey未配置")
+	}
+	signBytes, err := base64.StdEncoding.DecodeString(sign)
+	if err != nil {
+		return err
+	}
+	return RSAVerify(mapiSignContent(params), signBytes, signType, mapi.publicKey)
+}
+
+//生成签名后的请求参数
+func (mapi *Mapi) BuildQuery(service string, params map[string]string) (url.Values, error) {
+	query := map[string]string{
+		"service":        service,
+		"partner":        mapi.conf.Partner,
+		"_input_charset": "utf-8",
+	}
+	for k, v := range params {
+		query[k] = v
+	}
+	sign, err := mapi.Sign(query)
+	if err != nil {
+		return nil, err
+	}
+	query["sign"] = sign
+	query["sign_type"] = mapi.conf.SignType
+	values := url.Values{}
+	for k, v := range query {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+	return values, nil
+}
+
+type mapiResponse struct {
+	IsSuccess string    `xml:"is_success"`
+	Error     string    `xml:"error"`
+	Trade     utils.Xml `xml:"response>trade"`
+	Sign      string    `xml:"sign"`
+	SignType  string    `xml:"sign_type"`
+}
+
+//请求旧版网关并解析xml响应 resp不为nil时要求响应带签名的trade节点，验签后填充到resp
+func (mapi *Mapi) Request(service string, params map[string]string, resp interface{}) (data string, e error) {
+	query, err := mapi.BuildQuery(service, params)
+	if err != nil {
+		return "", err
+	}
+	body, err := utils.HttpPost(mapi.Gateway()+"?_input_charset=utf-8", "application/x-www-form-urlencoded", []byte(query.Encode()))
+	if err != nil {
+		return "", err
+	}
+	data = string(body)
+	var result mapiResponse
+	if err = xml.Unmarshal(body, &result); err != nil {
+		return data, err
+	}
+	if result.IsSuccess != "T" {
+		if result.Error == "" {
+			return data, errors.New("支付宝返回内容异常: " + data)
+		}
+		return data, &MapiError{Code: result.Error}
+	}
+	if resp == nil {
+		return data, nil
+	}
+	if len(result.Trade) == 0 {
+		return data, errors.New("response error: trade not found")
+	}
+	if result.Sign == "" {
+		return data, errors.New("response error: sign not found")
+	}
+	trade := map[string]string(result.Trade)
+	trade["sign"] = result.Sign
+	trade["sign_type"] = result.SignType
+	err = mapi.VerifySign(trade)
+	delete(trade, "sign")
+	delete(trade, "sign_type")
+	if err != nil {
+		return data, err
+	}
+	tradeBytes, _ := json.Marshal(result.Trade)
+	return data, json.Unmarshal(tradeBytes, resp)
+}
+
+//境外收单 创建交易
+type MapiCreateForexTradeParams struct {
+	OutTradeNo                string `json:"out_trade_no"`                          //商户订单号 必填
+	Subject                   string `json:"subject"`                               //订单标题 必填
+	Body                      string `json:"body,omitempty"`                        //订单描述
+	Currency                  string `json:"currency"`                              //结算币种 如USD 必填
+	TotalFee                  string `json:"total_fee,omitempty"`                   //外币金额 与rmb_fee二选一
+	RmbFee                    string `json:"rmb_fee,omitempty"`                     //人民币金额 与total_fee二选一
+	ProductCode               string `json:"product_code"`                          //产品码 NEW_OVERSEAS_SELLER/NEW_WAP_OVERSEAS_SELLER
+	OrderGmtCreate            string `json:"order_gmt_create,omitempty"`            //订单创建时间 yyyy-MM-dd HH:mm:ss
+	OrderValidTime            string `json:"order_valid_time,omitempty"`            //订单有效时间 单位秒
+	TimeoutRule               string `json:"timeout_rule,omitempty"`                //超时时间 如12h
+	Supplier                  string `json:"supplier,omitempty"`                    //商户名称
+	SecondaryMerchantId       string `json:"secondary_merchant_id,omitempty"`       //二级商户编号
+	SecondaryMerchantName     string `json:"secondary_merchant_name,omitempty"`     //二级商户名称
+	SecondaryMerchantIndustry string `json:"secondary_merchant_industry,omitempty"` //二级商户行业编码
+	ReferUrl                  string `json:"refer_url,omitempty"`                   //商户网站地址
+	SpecifiedPayChannel       string `json:"specified_pay_channel,omitempty"`       //指定支付渠道
+	SplitFundInfo             string `json:"split_fund_info,omitempty"`             //分账信息
+	TradeInformation          string `json:"trade_information,omitempty"`           //交易信息 行业相关的json串
+	ReturnUrl                 string `json:"return_url,omitempty"`                  //支付完成后的同步跳转地址
+	NotifyUrl                 string `json:"notify_url,omitempty"`                  //异步通知地址 默认payNotifyURL
+}
+
+func (params MapiCreateForexTradeParams) check() error {
+	if params.OutTradeNo == "" {
+		return errors.New("outTradeNo未填写")
+	}
+	if params.Subject == "" {
+		return errors.New("subject未填写")
+	}
+	if params.Currency == "" {
+		return errors.New("currency未填写")
+	}
+	if (params.TotalFee == "") == (params.RmbFee == "") {
+		return errors.New("totalFee和rmbFee必须填写一项")
+	}
+	return nil
+}
+
+func (mapi *Mapi) createForexTradeQuery(params MapiCreateForexTradeParams) (url.Values, error) {
+	if err := params.check(); err != nil {
+		return nil, err
+	}
+	if params.ProductCode == "" {
+		params.ProductCode = ProductCodeNewOverseasSeller
+	}
+	if params.NotifyUrl == "" {
+		params.NotifyUrl = mapi.conf.PayNotifyURL
+	}
+	return mapi.BuildQuery(ServiceCreateForexTrade, structToMap(params))
+}
+
+//境外收单 返回GET跳转地址
+func (mapi *Mapi) CreateForexTradeURL(params MapiCreateForexTradeParams) (string, error) {
+	query, err := mapi.createForexTradeQuery(params)
+	if err != nil {
+		return "", err
+	}
+	return mapi.Gateway() + "?" + query.Encode(), nil
+}
+
+//境外收单 返回自动提交的POST表单
+func (mapi *Mapi) CreateForexTradeForm(params MapiCreateForexTradeParams) (string, error) {
+	query, err := mapi.createForexTradeQuery(params)
+	if err != nil {
+		return "", err
+	}
+	return buildForm(mapi.Gateway()+"?_input_charset=utf-8", query), nil
+}
+
+//境外收单 退款
+type MapiForexRefundParams struct {
+	OutReturnNo     string `json:"out_return_no"`               //商户退款流水号 必填
+	OutTradeNo      string `json:"out_trade_no"`                //原交易商户订单号 必填
+	Currency        string `json:"currency"`                    //退款币种 必填
+	ReturnAmount    string `json:"return_amount,omitempty"`     //外币退款金额 与return_rmb_amount二选一
+	ReturnRmbAmount string `json:"return_rmb_amount,omitempty"` //人民币退款金额 与return_amount二选一
+	GmtReturn       string `json:"gmt_return,omitempty"`        //退款时间 yyyyMMddHHmmss 北京时间 默认当前时间
+	Reason          string `json:"reason,omitempty"`            //退款原因
+	ProductCode     string `json:"product_code,omitempty"`      //产品码 与下单时一致
+	SplitFundInfo   string `json:"split_fund_info,omitempty"`   //分账退款信息
+	IsSync          string `json:"is_sync,omitempty"`           //是否同步返回退款结果 Y/N
+}
+
+func (mapi *Mapi) ForexRefund(params MapiForexRefundParams) (string, error) {
+	if params.OutReturnNo == "" {
+		return "", errors.New("outReturnNo未填写")
+	}
+	if params.OutTradeNo == "" {
+		return "", errors.New("outTradeNo未填写")
+	}
+	if params.Currency == "" {
+		return "", errors.New("currency未填写")
+	}
+	if (params.ReturnAmount == "") == (params.ReturnRmbAmount == "") {
+		return "", errors.New("returnAmount和returnRmbAmount必须填写一项")
+	}
+	if params.GmtReturn == "" {
+		params.GmtReturn = time.Now().In(chinaLocation).Format("20060102150405")
+	}
+	if params.ProductCode == "" {
+		params.ProductCode = ProductCodeNewOverseasSeller
+	}
+	return mapi.Request(ServiceForexRefund, structToMap(params), nil)
+}
+
+//单笔交易查询
+type MapiSingleTradeQueryParams struct {
+	TradeNo    string `json:"trade_no,omitempty"`     //支付宝交易号 与out_trade_no二选一
+	OutTradeNo string `json:"out_trade_no,omitempty"` //商户订单号 与trade_no二选一
+}
+
+type MapiSingleTradeQueryResult struct {
+	TradeNo             string `json:"trade_no"`               //支付宝交易号
+	OutTradeNo          string `json:"out_trade_no"`           //商户订单号
+	TradeStatus         string `json:"trade_status"`           //交易状态
+	Subject             string `json:"subject"`                //订单标题
+	Body                string `json:"body"`                   //订单描述
+	TotalFee            string `json:"total_fee"`              //交易金额
+	Price               string `json:"price"`                  //商品单价
+	Quantity            string `json:"quantity"`               //购买数量
+	Currency            string `json:"currency"`               //币种
+	ForexTotalFee       string `json:"forex_total_fee"`        //外币金额
+	BuyerEmail          string `json:"buyer_email"`            //买家支付宝账号
+	BuyerId             string `json:"buyer_id"`               //买家支付宝用户号
+	SellerEmail         string `json:"seller_email"`           //卖家支付宝账号
+	SellerId            string `json:"seller_id"`              //卖家支付宝用户号
+	GmtCreate           string `json:"gmt_create"`             //交易创建时间
+	GmtPayment          string `json:"gmt_payment"`            //交易付款时间
+	GmtClose            string `json:"gmt_close"`              //交易关闭时间
+	GmtLastModifiedTime string `json:"gmt_last_modified_time"` //最近修改时间
+	RefundStatus        string `json:"refund_status"`          //退款状态
+	IsTotalFeeAdjust    string `json:"is_total_fee_adjust"`    //总价是否调整过 T/F
+	UseCoupon           string `json:"use_coupon"`             //是否使用红包 T/F
+	Discount            string `json:"discount"`               //折扣
+}
+
+func (mapi *Mapi) SingleTradeQuery(params MapiSingleTradeQueryParams) (*MapiSingleTradeQueryResult, string, error) {
+	if params.TradeNo == "" && params.OutTradeNo == "" {
+		return nil, "", errors.New("tradeNo和outTradeNo必须填写一项")
+	}
+	var result MapiSingleTradeQueryResult
+	data, err := mapi.Request(ServiceSingleTradeQuery, structToMap(params), &result)
+	return &result, data, err
+}
+
+//旧版异步通知
+type MapiNotifyResp struct {
+	NotifyId      string `json:"notify_id"`
+	NotifyTime    string `json:"notify_time"`
+	NotifyType    string `json:"notify_type"`
+	SignType      string `json:"sign_type"`
+	Sign          string `json:"sign"`
+	TradeNo       string `json:"trade_no"`
+	OutTradeNo    string `json:"out_trade_no"`
+	TradeStatus   string `json:"trade_status"`
+	Subject       string `json:"subject"`
+	Body          string `json:"body"`
+	Currency      string `json:"currency"`
+	TotalFee      string `json:"total_fee"`
+	RmbFee        string `json:"rmb_fee"`
+	ForexTotalFee string `json:"forex_total_fee"`
+	BuyerEmail    string `json:"buyer_email"`
+	BuyerId       string `json:"buyer_id"`
+	SellerEmail   string `json:"seller_email"`
+	SellerId      string `json:"seller_id"`
+	GmtCreate     string `json:"gmt_create"`
+	GmtPayment    string `json:"gmt_payment"`
+	GmtClose      string `json:"gmt_close"`
+	RefundStatus  string `json:"refund_status"`
+	OutReturnNo   string `json:"out_return_no"`
+}
+
+//校验签名后调用notify_verify确认通知由支付宝发出
+func (mapi *Mapi) Notify(params map[string]string) (*MapiNotifyResp, error) {
+	if err := mapi.VerifySign(params); err != nil {
+		return nil, err
+	}
+	ok, err := mapi.NotifyVerify(params["notify_id"])
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("notify_verify校验失败")
+	}
+	var res MapiNotifyResp
+	paramsBytes, _ := json.Marshal(params)
+	if err = json.Unmarshal(paramsBytes, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+//notify_id一分钟内有效 返回true表示通知由支付宝发出
+func (mapi *Mapi) NotifyVerify(notifyId string) (bool, error) {
+	if notifyId == "" {
+		return false, errors.New("notifyId为空")
+	}
+	query := url.Values{}
+	query.Set("service", ServiceNotifyVerify)
+	query.Set("partner", mapi.conf.Partner)
+	query.Set("notify_id", notifyId)
+	body, err := utils.HttpGet(mapi.Gateway() + "?" + query.Encode())
+	if err != nil {
+		return false, err
+	}
+	switch strings.TrimSpace(string(body)) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("notify_verify返回异常: %s", body)
+}
+
+func (mapi *Mapi) NotifySuccess() string {
+	return "success"
+}
+
+func (mapi *Mapi) NotifyFail() string {
+	return "fail"
+}
+
+//按json标签转换为请求参数
+func structToMap(v interface{}) map[string]string {
+	params := make(map[string]string)
+	data, _ := json.Marshal(v)
+	_ = json.Unmarshal(data, &params)
+	return params
+}
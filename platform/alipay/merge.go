@@ -0,0 +1,110 @@
+package alipay
+
+import (
+	"errors"
+	"fmt"
+)
+
+//统一收单合并支付预创建 一次支付覆盖多个商户的订单
+//子订单的查询、退款等仍按out_trade_no调用TradeQuery、TradeRefund等单笔交易接口
+type TradeMergeOrderDetail struct {
+	AppId          string        `json:"app_id"`                    //订单所属应用的APPID 必填
+	OutTradeNo     string        `json:"out_trade_no"`              //商户订单号 必填
+	SellerId       string        `json:"seller_id,omitempty"`       //卖家支付宝用户ID
+	SellerLogonId  string        `json:"seller_logon_id,omitempty"` //卖家支付宝登录账号
+	ProductCode    string        `json:"product_code"`              //销售产品码 App支付QUICK_MSECURITY_PAY/手机网站支付QUICK_WAP_WAY 必填
+	TotalAmount    string        `json:"total_amount"`              //订单金额 必填
+	Subject        string        `json:"subject"`                   //订单标题 必填
+	Body           string        `json:"body,omitempty"`            //对商品的描述
+	ShowUrl        string        `json:"show_url,omitempty"`        //商品的展示地址
+	GoodsDetail    []GoodsDetail `json:"goods_detail,omitempty"`    //订单包含的商品列表信息
+	PassbackParams string        `json:"passback_params,omitempty"` //公用回传参数
+	ExtendParams   *ExtendParams `json:"extend_params,omitempty"`   //业务扩展参数
+	SettleInfo     *SettleInfo   `json:"settle_info,omitempty"`     //描述结算信息
+	SubMerchant    *SubMerchant  `json:"sub_merchant,omitempty"`    //二级商户信息
+}
+
+type SubMerchant struct {
+	MerchantId   string `json:"merchant_id"`             //间连受理商户的支付宝商户编号
+	MerchantType string `json:"merchant_type,omitempty"` //商户id类型 alipay/merchant
+}
+
+type TradeMergePrecreateParams struct {
+	OutMergeNo     string                  `json:"out_merge_no,omitempty"`    //合并支付订单号 不传时支付宝生成
+	TimeoutExpress string                  `json:"timeout_express,omitempty"` //合并支付最晚付款时间
+	OrderDetails   []TradeMergeOrderDetail `json:"order_details"`             //子订单详情 必填
+}
+
+type TradeMergeOrderDetailResult struct {
+	AppId      string `json:"app_id"`       //子订单所属应用的APPID
+	OutTradeNo string `json:"out_trade_no"` //商户订单号
+	Success    bool   `json:"success"`      //子订单是否预创建成功
+	ResultCode string `json:"result_code"`  //子订单预创建失败的错误码
+}
+
+type TradeMergePrecreateResult struct {
+	Result
+	OutMergeNo         string                        `json:"out_merge_no"`         //合并支付订单号
+	PreOrderNo         string                        `json:"pre_order_no"`         //预下单号 用于发起合并支付
+	OrderDetailResults []TradeMergeOrderDetailResult `json:"order_detail_results"` //子订单预创建结果
+}
+
+func (alipay *Alipay) TradeMergePrecreate(bizContent TradeMergePrecreateParams) (*TradeMergePrecreateResult, string, error) {
+	if len(bizContent.OrderDetails) == 0 {
+		return nil, "", errors.New("orderDetails未填写")
+	}
+	//复制子订单后再填充默认值，避免修改调用方的切片
+	bizContent.OrderDetails = append([]TradeMergeOrderDetail(nil), bizContent.OrderDetails...)
+	for i, detail := range bizContent.OrderDetails {
+		if detail.OutTradeNo == "" {
+			return nil, "", fmt.Errorf("orderDetails[%d].outTradeNo未填写", i)
+		}
+		if detail.TotalAmount == "" {
+			return nil, "", fmt.Errorf("orderDetails[%d].totalAmount未填写", i)
+		}
+		if detail.Subject == "" {
+			return nil, "", fmt.Errorf("orderDetails[%d].subject未填写", i)
+		}
+		if detail.ProductCode == "" {
+			return nil, "", fmt.Errorf("orderDetails[%d].productCode未填写", i)
+		}
+		if detail.AppId == "" {
+			bizContent.OrderDetails[i].AppId = alipay.conf.AppID
+		}
+	}
+	var result TradeMergePrecreateResult
+	data, err := alipay.Request(MethodTradeMergePrecreate, bizContent, &result)
+	return &result, data, err
+}
+
+type tradeMergePayParams struct {
+	PreOrderNo string `json:"pre_order_no"`
+}
+
+//App合并支付 返回客户端SDK调起支付所需的订单字符串，不发起请求
+func (alipay *Alipay) AppMergePay(preOrderNo string) (string, error) {
+	if preOrderNo == "" {
+		return "", errors.New("preOrderNo未填写")
+	}
+	params, err := alipay.BuildQuery(MethodTradeAppMergePay, tradeMergePayParams{PreOrderNo: preOrderNo})
+	if err != nil {
+		return "", err
+	}
+	return params.Encode(), nil
+}
+
+//手机网站合并支付 返回GET跳转地址
+func (alipay *Alipay) TradeWapMergePayURL(preOrderNo, returnUrl string) (string, error) {
+	if preOrderNo == "" {
+		return "", errors.New("preOrderNo未填写")
+	}
+	return alipay.BuildPageURL(MethodTradeWapMergePay, tradeMergePayParams{PreOrderNo: preOrderNo}, returnUrlParams(returnUrl))
+}
+
+//手机网站合并支付 返回自动提交的POST表单
+func (alipay *Alipay) TradeWapMergePayForm(preOrderNo, returnUrl string) (string, error) {
+	if preOrderNo == "" {
+		return "", errors.New("preOrderNo未填写")
+	}
+	return alipay.BuildPageForm(MethodTradeWapMergePay, tradeMergePayParams{PreOrderNo: preOrderNo}, returnUrlParams(returnUrl))
+}
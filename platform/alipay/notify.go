@@ -1,7 +1,6 @@
 package alipay
 
 import (
-	"encoding/base64"
 	"encoding/json"
 )
 
@@ -29,22 +28,102 @@ type NotifyPayResp struct {
 }
 
 func (alipay *Alipay) NotifyPay(params map[string]string) (*NotifyPayResp, error) {
-	sign := params["sign"]
-	signType := params["sign_type"]
-	params["sign"] = ""
-	params["sign_type"] = ""
-	content := alipay.GetSignContent(params)
-	signBytes, err := base64.StdEncoding.DecodeString(sign)
-	if err != nil {
+	var res NotifyPayResp
+	if err := alipay.VerifyNotify(params, &res); err != nil {
 		return nil, err
 	}
-	err = OpenSSLVerify(content, signBytes, signType, alipay.conf.AlipayPublicKey)
+	return &res, nil
+}
+
+//校验异步通知签名，通过后将参数填充到resp
+func (alipay *Alipay) VerifyNotify(params map[string]string, resp interface{}) error {
+	content := make(map[string]string, len(params))
+	for k, v := range params {
+		if k == "sign" || k == "sign_type" {
+			continue
+		}
+		content[k] = v
+	}
+	err := alipay.VerifySign(alipay.GetSignContent(content), params["sign"], params["sign_type"], "")
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var res NotifyPayResp
 	paramsBytes, _ := json.Marshal(params)
-	_ = json.Unmarshal(paramsBytes, &res)
+	return json.Unmarshal(paramsBytes, resp)
+}
+
+//资金授权冻结、解冻异步通知
+type NotifyFundAuthResp struct {
+	NotifyId            string `json:"notify_id"`
+	NotifyTime          string `json:"notify_time"`
+	NotifyType          string `json:"notify_type"` //fund_auth_freeze/fund_auth_unfreeze
+	SignType            string `json:"sign_type"`
+	Sign                string `json:"sign"`
+	AuthNo              string `json:"auth_no"`
+	OutOrderNo          string `json:"out_order_no"`
+	OperationId         string `json:"operation_id"`
+	OutRequestNo        string `json:"out_request_no"`
+	OperationType       string `json:"operation_type"`
+	Amount              string `json:"amount"`
+	Status              string `json:"status"`
+	GmtCreate           string `json:"gmt_create"`
+	GmtTrans            string `json:"gmt_trans"`
+	PayerLogonId        string `json:"payer_logon_id"`
+	PayerUserId         string `json:"payer_user_id"`
+	PayeeLogonId        string `json:"payee_logon_id"`
+	PayeeUserId         string `json:"payee_user_id"`
+	TotalFreezeAmount   string `json:"total_freeze_amount"`
+	TotalUnfreezeAmount string `json:"total_unfreeze_amount"`
+	TotalPayAmount      string `json:"total_pay_amount"`
+	RestAmount          string `json:"rest_amount"`
+	CreditAmount        string `json:"credit_amount"`
+	FundAmount          string `json:"fund_amount"`
+	PreAuthType         string `json:"pre_auth_type"`
+	TransCurrency       string `json:"trans_currency"`
+}
+
+func (alipay *Alipay) NotifyFundAuth(params map[string]string) (*NotifyFundAuthResp, error) {
+	var res NotifyFundAuthResp
+	if err := alipay.VerifyNotify(params, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+//代扣协议签约、解约异步通知
+type NotifyAgreementResp struct {
+	NotifyId            string `json:"notify_id"`
+	NotifyTime          string `json:"notify_time"`
+	NotifyType          string `json:"notify_type"` //dut_user_sign/dut_user_unsign
+	SignType            string `json:"sign_type"`
+	Sign                string `json:"sign"`
+	AppId               string `json:"app_id"`
+	AuthAppId           string `json:"auth_app_id"`
+	AgreementNo         string `json:"agreement_no"`
+	ExternalAgreementNo string `json:"external_agreement_no"`
+	PersonalProductCode string `json:"personal_product_code"`
+	SignScene           string `json:"sign_scene"`
+	Status              string `json:"status"`
+	AlipayUserId        string `json:"alipay_user_id"`
+	AlipayOpenId        string `json:"alipay_open_id"`
+	AlipayLogonId       string `json:"alipay_logon_id"`
+	ExternalLogonId     string `json:"external_logon_id"`
+	PartnerId           string `json:"partner_id"`
+	ZmOpenId            string `json:"zm_open_id"`
+	CreditAuthMode      string `json:"credit_auth_mode"`
+	SingleQuota         string `json:"single_quota"`
+	SignTime            string `json:"sign_time"`
+	ValidTime           string `json:"valid_time"`
+	InvalidTime         string `json:"invalid_time"`
+	UnsignTime          string `json:"unsign_time"`
+	NextDeductTime      string `json:"next_deduct_time"`
+}
+
+func (alipay *Alipay) NotifyAgreement(params map[string]string) (*NotifyAgreementResp, error) {
+	var res NotifyAgreementResp
+	if err := alipay.VerifyNotify(params, &res); err != nil {
+		return nil, err
+	}
 	return &res, nil
 }
 
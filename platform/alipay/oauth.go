@@ -0,0 +1,84 @@
+package alipay
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+//换取授权访问令牌
+type SystemOauthTokenResult struct {
+	Result
+	UserId       string      `json:"user_id"`       //支付宝用户的唯一标识
+	OpenId       string      `json:"open_id"`       //支付宝用户在应用下的唯一标识
+	AccessToken  string      `json:"access_token"`  //访问令牌
+	ExpiresIn    json.Number `json:"expires_in"`    //访问令牌的有效时间，单位秒
+	RefreshToken string      `json:"refresh_token"` //刷新令牌
+	ReExpiresIn  json.Number `json:"re_expires_in"` //刷新令牌的有效时间，单位秒
+	AuthStart    string      `json:"auth_start"`    //授权token开始时间
+}
+
+//访问令牌过期时间 以授权开始时间计算
+func (result *SystemOauthTokenResult) AccessTokenExpiresAt() time.Time {
+	return result.expiresAt(result.ExpiresIn)
+}
+
+//刷新令牌过期时间 以授权开始时间计算
+func (result *SystemOauthTokenResult) RefreshTokenExpiresAt() time.Time {
+	return result.expiresAt(result.ReExpiresIn)
+}
+
+func (result *SystemOauthTokenResult) expiresAt(expiresIn json.Number) time.Time {
+	start, err := time.ParseInLocation("2006-01-02 15:04:05", result.AuthStart, chinaLocation)
+	if err != nil {
+		start = time.Now()
+	}
+	seconds, _ := expiresIn.Int64()
+	return start.Add(time.Duration(seconds) * time.Second)
+}
+
+//支付宝时间均为北京时间
+var chinaLocation = time.FixedZone("CST", 8*3600)
+
+//使用授权码换取访问令牌
+func (alipay *Alipay) SystemOauthToken(code string) (*SystemOauthTokenResult, string, error) {
+	if code == "" {
+		return nil, "", errors.New("code未填写")
+	}
+	return alipay.systemOauthToken(map[string]string{"grant_type": GrantTypeAuthorizationCode, "code": code})
+}
+
+//使用刷新令牌换取新的访问令牌
+func (alipay *Alipay) SystemOauthRefreshToken(refreshToken string) (*SystemOauthTokenResult, string, error) {
+	if refreshToken == "" {
+		return nil, "", errors.New("refreshToken未填写")
+	}
+	return alipay.systemOauthToken(map[string]string{"grant_type": GrantTypeRefreshToken, "refresh_token": refreshToken})
+}
+
+func (alipay *Alipay) systemOauthToken(params map[string]string) (*SystemOauthTokenResult, string, error) {
+	var result SystemOauthTokenResult
+	data, err := alipay.RequestWithParams(MethodSystemOauthToken, nil, params, &result)
+	return &result, data, err
+}
+
+//支付宝会员授权信息查询
+type UserInfoShareResult struct {
+	Result
+	UserId   string `json:"user_id"`   //支付宝用户的userId
+	OpenId   string `json:"open_id"`   //支付宝用户在应用下的唯一标识
+	Avatar   string `json:"avatar"`    //用户头像地址
+	Province string `json:"province"`  //省份名称
+	City     string `json:"city"`      //市名称
+	NickName string `json:"nick_name"` //用户昵称
+	Gender   string `json:"gender"`    //性别 F女性 M男性
+}
+
+func (alipay *Alipay) UserInfoShare(accessToken string) (*UserInfoShareResult, string, error) {
+	if accessToken == "" {
+		return nil, "", errors.New("accessToken未填写")
+	}
+	var result UserInfoShareResult
+	data, err := alipay.RequestWithParams(MethodUserInfoShare, nil, map[string]string{"auth_token": accessToken}, &result)
+	return &result, data, err
+}
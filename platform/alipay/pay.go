@@ -0,0 +1,182 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+//统一收单交易支付（当面付条码支付、刷脸支付）
+type TradePayParams struct {
+	OutTradeNo         string           `json:"out_trade_no"`                  //商户订单号 必填
+	Scene              string           `json:"scene,omitempty"`               //支付场景 bar_code/face_code 条码支付必填
+	AuthCode           string           `json:"auth_code,omitempty"`           //支付授权码 条码支付必填
+	AuthNo             string           `json:"auth_no,omitempty"`             //资金预授权单号 预授权转支付时必填
+	AuthConfirmMode    string           `json:"auth_confirm_mode,omitempty"`   //预授权确认模式 COMPLETE转交易完成后解冻剩余冻结金额/NOT_COMPLETE不解冻
+	Subject            string           `json:"subject"`                       //订单标题 必填
+	ProductCode        string           `json:"product_code,omitempty"`        //销售产品码 FACE_TO_FACE_PAYMENT
+	BuyerId            string           `json:"buyer_id,omitempty"`            //买家的支付宝用户id
+	SellerId           string           `json:"seller_id,omitempty"`           //支付宝用户ID
+	TotalAmount        string           `json:"total_amount"`                  //订单金额 必填
+	TransCurrency      string           `json:"trans_currency,omitempty"`      //标价币种
+	SettleCurrency     string           `json:"settle_currency,omitempty"`     //商户指定的结算币种
+	DiscountableAmount string           `json:"discountable_amount,omitempty"` //可打折金额
+	Body               string           `json:"body,omitempty"`                //对商品的描述
+	GoodsDetail        []GoodsDetail    `json:"goods_detail,omitempty"`        //订单包含的商品列表信息
+	OperatorId         string           `json:"operator_id,omitempty"`         //商户操作员编码
+	StoreId            string           `json:"store_id,omitempty"`            //商户门店编码
+	TerminalId         string           `json:"terminal_id,omitempty"`         //终端id
+	TimeoutExpress     string           `json:"timeout_express,omitempty"`     //该笔订单允许的最晚付款时间
+	QueryOptions       []string         `json:"query_options,omitempty"`       //查询选项
+	ExtendParams       *ExtendParams    `json:"extend_params,omitempty"`       //业务扩展参数
+	SettleInfo         *SettleInfo      `json:"settle_info,omitempty"`         //描述结算信息
+	AgreementParams    *AgreementParams `json:"agreement_params,omitempty"`    //代扣信息 协议代扣时必填
+}
+
+type TradePayResult struct {
+	Result
+	TradeNo             string          `json:"trade_no"`              //支付宝交易号
+	OutTradeNo          string          `json:"out_trade_no"`          //商户订单号
+	BuyerLogonId        string          `json:"buyer_logon_id"`        //买家支付宝账号
+	SettleAmount        string          `json:"settle_amount"`         //结算币种订单金额
+	PayCurrency         string          `json:"pay_currency"`          //支付币种
+	PayAmount           string          `json:"pay_amount"`            //支付币种订单金额
+	SettleTransRate     string          `json:"settle_trans_rate"`     //结算币种兑换标价币种汇率
+	TransPayRate        string          `json:"trans_pay_rate"`        //标价币种兑换支付币种汇率
+	TotalAmount         string          `json:"total_amount"`          //交易金额
+	TransCurrency       string          `json:"trans_currency"`        //标价币种
+	SettleCurrency      string          `json:"settle_currency"`       //商户指定的结算币种
+	ReceiptAmount       string          `json:"receipt_amount"`        //实收金额
+	BuyerPayAmount      string          `json:"buyer_pay_amount"`      //买家付款的金额
+	PointAmount         string          `json:"point_amount"`          //使用集分宝付款的金额
+	InvoiceAmount       string          `json:"invoice_amount"`        //交易中可给用户开具发票的金额
+	GmtPayment          string          `json:"gmt_payment"`           //交易支付时间
+	FundBillList        []TradeFundBill `json:"fund_bill_list"`        //交易支付使用的资金渠道
+	StoreName           string          `json:"store_name"`            //发生支付交易的商户门店名称
+	BuyerUserId         string          `json:"buyer_user_id"`         //买家在支付宝的用户id
+	BuyerOpenId         string          `json:"buyer_open_id"`         //买家支付宝用户唯一标识
+	DiscountGoodsDetail string          `json:"discount_goods_detail"` //本次交易支付所使用的单品券优惠的商品优惠信息
+	MdiscountAmount     string          `json:"mdiscount_amount"`      //商家优惠金额
+	DiscountAmount      string          `json:"discount_amount"`       //平台优惠金额
+	AdvanceAmount       string          `json:"advance_amount"`        //先享后付2.0垫资金额
+	AsyncPaymentMode    string          `json:"async_payment_mode"`    //异步支付模式
+}
+
+func (alipay *Alipay) TradePay(bizContent TradePayParams) (*TradePayResult, string, error) {
+	if bizContent.OutTradeNo == "" {
+		return nil, "", errors.New("outTradeNo未填写")
+	}
+	if bizContent.AgreementParams != nil {
+		//协议代扣
+		if bizContent.AgreementParams.AgreementNo == "" {
+			return nil, "", errors.New("agreementParams.agreementNo未填写")
+		}
+		if bizContent.ProductCode == "" {
+			bizContent.ProductCode = ProductCodeGeneralWithholding
+		}
+	} else if bizContent.AuthNo != "" {
+		//预授权转支付
+		if bizContent.ProductCode == "" {
+			bizContent.ProductCode = ProductCodePreAuth
+		}
+	} else {
+		if bizContent.AuthCode == "" {
+			return nil, "", errors.New("authCode未填写")
+		}
+		if bizContent.Scene == "" {
+			bizContent.Scene = SceneBarCode
+		}
+	}
+	var result TradePayResult
+	data, err := alipay.Request(MethodTradePay, bizContent, &result)
+	return &result, data, err
+}
+
+//等待用户付款的轮询配置
+type TradePayWaitOptions struct {
+	Interval    time.Duration //查询间隔 默认5秒
+	Timeout     time.Duration //最长等待时间 超时后撤销交易 默认30秒
+	CancelRetry int           //撤销交易最大重试次数 默认3次
+}
+
+//付款最终结果
+type TradePayOutcome struct {
+	Status string             //PayOutcomeSuccess/PayOutcomeClosed/PayOutcomeCancelled/PayOutcomeFailed
+	Pay    *TradePayResult    //支付接口返回
+	Query  *TradeQueryResult  //最后一次查询返回
+	Cancel *TradeCancelResult //撤销接口返回
+}
+
+//条码支付并等待最终结果
+//支付宝返回10003（等待用户输入密码）或20000（结果未知）时按间隔查询订单，
+//至少查询一次，超时仍未支付成功则撤销交易
+//ctx取消时（如收银员终止收款）中断进行中的请求并撤销交易，返回ctx.Err()
+//撤销不受ctx影响，以免交易停留在未知状态，最长耗时为opts.Timeout
+func (alipay *Alipay) TradePayAndWait(ctx context.Context, bizContent TradePayParams, opts TradePayWaitOptions) (*TradePayOutcome, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.CancelRetry <= 0 {
+		opts.CancelRetry = 3
+	}
+	client := alipay.WithContext(ctx)
+	deadline := time.Now().Add(opts.Timeout)
+	payResult, _, err := client.TradePay(bizContent)
+	if payResult == nil {
+		return nil, err
+	}
+	outcome := &TradePayOutcome{Pay: payResult}
+	if err == nil {
+		outcome.Status = PayOutcomeSuccess
+		return outcome, nil
+	}
+	//明确失败直接返回，等待付款、结果未知或网络异常时查询订单
+	if alipayErr, ok := AsAlipayError(err); ok && !alipayErr.IsWaitUserPay() && !alipayErr.IsRetryable() {
+		outcome.Status = PayOutcomeFailed
+		return outcome, err
+	}
+	var ctxErr error
+	for {
+		wait := time.Until(deadline)
+		if wait > opts.Interval {
+			wait = opts.Interval
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			ctxErr = ctx.Err()
+		case <-timer.C:
+		}
+		if ctxErr != nil {
+			break
+		}
+		queryResult, _, err := client.TradeQuery(TradeQueryParams{OutTradeNo: bizContent.OutTradeNo})
+		if err == nil {
+			outcome.Query = queryResult
+			switch queryResult.TradeStatus {
+			case TradeStatusSuccess, TradeStatusFinished:
+				outcome.Status = PayOutcomeSuccess
+				return outcome, nil
+			case TradeStatusClosed:
+				outcome.Status = PayOutcomeClosed
+				return outcome, nil
+			}
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+	}
+	cancelCtx, stop := context.WithTimeout(context.Background(), opts.Timeout)
+	defer stop()
+	cancelResult, _, err := alipay.TradeCancelUntilDone(cancelCtx, TradeCancelParams{OutTradeNo: bizContent.OutTradeNo}, opts.CancelRetry, opts.Interval)
+	outcome.Cancel = cancelResult
+	if err != nil {
+		return outcome, err
+	}
+	outcome.Status = PayOutcomeCancelled
+	return outcome, ctxErr
+}
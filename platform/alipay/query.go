@@ -1 +1,76 @@
 package alipay
+
+import "errors"
+
+//统一收单线下交易查询
+type TradeQueryParams struct {
+	OutTradeNo   string   `json:"out_trade_no,omitempty"`  //商户订单号 与trade_no二选一
+	TradeNo      string   `json:"trade_no,omitempty"`      //支付宝交易号 与out_trade_no二选一
+	OrgPid       string   `json:"org_pid,omitempty"`       //银行间联模式下有用
+	QueryOptions []string `json:"query_options,omitempty"` //查询选项 如fund_bill_list、voucher_detail_list
+}
+
+type TradeFundBill struct {
+	FundChannel string `json:"fund_channel"` //交易使用的资金渠道
+	Amount      string `json:"amount"`       //该支付工具类型所使用的金额
+	RealAmount  string `json:"real_amount"`  //渠道实际付款金额
+	FundType    string `json:"fund_type"`    //渠道所使用的资金类型
+}
+
+type TradeVoucherDetail struct {
+	Id                         string `json:"id"`                           //券id
+	Name                       string `json:"name"`                         //券名称
+	Type                       string `json:"type"`                         //券类型
+	Amount                     string `json:"amount"`                       //优惠券面额
+	MerchantContribute         string `json:"merchant_contribute"`          //商家出资
+	OtherContribute            string `json:"other_contribute"`             //其他出资方出资金额
+	Memo                       string `json:"memo"`                         //优惠券备注信息
+	TemplateId                 string `json:"template_id"`                  //券模板id
+	PurchaseBuyerContribute    string `json:"purchase_buyer_contribute"`    //用户购买券时用户实际付款的金额
+	PurchaseMerchantContribute string `json:"purchase_merchant_contribute"` //用户购买券时商户出资金额
+	PurchaseAntContribute      string `json:"purchase_ant_contribute"`      //用户购买券时平台优惠的金额
+}
+
+type TradeQueryResult struct {
+	Result
+	TradeNo             string               `json:"trade_no"`               //支付宝交易号
+	OutTradeNo          string               `json:"out_trade_no"`           //商户订单号
+	BuyerLogonId        string               `json:"buyer_logon_id"`         //买家支付宝账号
+	TradeStatus         string               `json:"trade_status"`           //交易状态
+	TotalAmount         string               `json:"total_amount"`           //交易的订单金额
+	TransCurrency       string               `json:"trans_currency"`         //标价币种
+	SettleCurrency      string               `json:"settle_currency"`        //订单结算币种
+	SettleAmount        string               `json:"settle_amount"`          //结算币种订单金额
+	PayCurrency         string               `json:"pay_currency"`           //订单支付币种
+	PayAmount           string               `json:"pay_amount"`             //支付币种订单金额
+	SettleTransRate     string               `json:"settle_trans_rate"`      //结算币种兑换标价币种汇率
+	TransPayRate        string               `json:"trans_pay_rate"`         //标价币种兑换支付币种汇率
+	BuyerPayAmount      string               `json:"buyer_pay_amount"`       //买家实付金额
+	PointAmount         string               `json:"point_amount"`           //积分支付的金额
+	InvoiceAmount       string               `json:"invoice_amount"`         //交易中用户支付的可开具发票的金额
+	SendPayDate         string               `json:"send_pay_date"`          //本次交易打款给卖家的时间
+	ReceiptAmount       string               `json:"receipt_amount"`         //实收金额
+	StoreId             string               `json:"store_id"`               //商户门店编号
+	TerminalId          string               `json:"terminal_id"`            //商户机具终端编号
+	StoreName           string               `json:"store_name"`             //请求交易支付中的商户店铺的名称
+	BuyerUserId         string               `json:"buyer_user_id"`          //买家在支付宝的用户id
+	BuyerOpenId         string               `json:"buyer_open_id"`          //买家支付宝用户唯一标识
+	BuyerUserType       string               `json:"buyer_user_type"`        //买家用户类型
+	MdiscountAmount     string               `json:"mdiscount_amount"`       //商家优惠金额
+	DiscountAmount      string               `json:"discount_amount"`        //平台优惠金额
+	Subject             string               `json:"subject"`                //订单标题
+	Body                string               `json:"body"`                   //订单描述
+	AlipaySubMerchantId string               `json:"alipay_sub_merchant_id"` //间连商户在支付宝端的商户编号
+	ExtInfos            string               `json:"ext_infos"`              //交易额外信息
+	FundBillList        []TradeFundBill      `json:"fund_bill_list"`         //交易支付使用的资金渠道
+	VoucherDetailList   []TradeVoucherDetail `json:"voucher_detail_list"`    //本交易支付时使用的所有优惠券信息
+}
+
+func (alipay *Alipay) TradeQuery(bizContent TradeQueryParams) (*TradeQueryResult, string, error) {
+	if bizContent.OutTradeNo == "" && bizContent.TradeNo == "" {
+		return nil, "", errors.New("outTradeNo和tradeNo必须填写一项")
+	}
+	var result TradeQueryResult
+	data, err := alipay.Request(MethodTradeQuery, bizContent, &result)
+	return &result, data, err
+}
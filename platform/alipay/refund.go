@@ -0,0 +1,125 @@
+package alipay
+
+import "errors"
+
+//统一收单交易退款
+type TradeRefundParams struct {
+	OutTradeNo              string              `json:"out_trade_no,omitempty"`              //商户订单号 与trade_no二选一
+	TradeNo                 string              `json:"trade_no,omitempty"`                  //支付宝交易号 与out_trade_no二选一
+	RefundAmount            string              `json:"refund_amount"`                       //退款金额 必填
+	RefundCurrency          string              `json:"refund_currency,omitempty"`           //订单退款币种信息
+	RefundReason            string              `json:"refund_reason,omitempty"`             //退款原因说明
+	OutRequestNo            string              `json:"out_request_no,omitempty"`            //退款请求号 部分退款时必填
+	OperatorId              string              `json:"operator_id,omitempty"`               //商户操作员编号
+	StoreId                 string              `json:"store_id,omitempty"`                  //商户门店编号
+	TerminalId              string              `json:"terminal_id,omitempty"`               //商户机具终端编号
+	OrgPid                  string              `json:"org_pid,omitempty"`                   //银行间联模式下有用
+	GoodsDetail             []GoodsDetail       `json:"goods_detail,omitempty"`              //退款包含的商品列表信息
+	RefundRoyaltyParameters []RoyaltyParameters `json:"refund_royalty_parameters,omitempty"` //退分账明细信息
+	QueryOptions            []string            `json:"query_options,omitempty"`             //查询选项
+}
+
+type GoodsDetail struct {
+	GoodsId        string `json:"goods_id"`                  //商品的编号 必填
+	AlipayGoodsId  string `json:"alipay_goods_id,omitempty"` //支付宝定义的统一商品编号
+	GoodsName      string `json:"goods_name"`                //商品名称 必填
+	Quantity       int    `json:"quantity"`                  //商品数量 必填
+	Price          string `json:"price"`                     //商品单价 必填
+	GoodsCategory  string `json:"goods_category,omitempty"`  //商品类目
+	CategoriesTree string `json:"categories_tree,omitempty"` //商品类目树
+	Body           string `json:"body,omitempty"`            //商品描述信息
+	ShowUrl        string `json:"show_url,omitempty"`        //商品的展示地址
+}
+
+type TradeRefundResult struct {
+	Result
+	TradeNo                 string                `json:"trade_no"`                   //支付宝交易号
+	OutTradeNo              string                `json:"out_trade_no"`               //商户订单号
+	BuyerLogonId            string                `json:"buyer_logon_id"`             //用户的登录id
+	FundChange              string                `json:"fund_change"`                //本次退款是否发生了资金变化
+	RefundFee               string                `json:"refund_fee"`                 //退款总金额
+	RefundCurrency          string                `json:"refund_currency"`            //退款币种信息
+	GmtRefundPay            string                `json:"gmt_refund_pay"`             //退款支付时间
+	StoreName               string                `json:"store_name"`                 //交易在支付时候的门店名称
+	BuyerUserId             string                `json:"buyer_user_id"`              //买家在支付宝的用户id
+	BuyerOpenId             string                `json:"buyer_open_id"`              //买家支付宝用户唯一标识
+	SendBackFee             string                `json:"send_back_fee"`              //本次商户实际退回金额
+	RefundHybAmount         string                `json:"refund_hyb_amount"`          //本次请求退惠营宝金额
+	RefundDetailItemList    []TradeFundBill       `json:"refund_detail_item_list"`    //退款使用的资金渠道
+	RefundChargeInfoList    []RefundChargeInfo    `json:"refund_charge_info_list"`    //退费信息
+	RefundPresetPaytoolList []RefundPresetPaytool `json:"refund_preset_paytool_list"` //退回的前置资产列表
+}
+
+type RefundChargeInfo struct {
+	RefundChargeFee        string `json:"refund_charge_fee"` //实退费用
+	SwitchFeeRate          string `json:"switch_fee_rate"`   //签约费率
+	ChargeType             string `json:"charge_type"`       //收单手续费trade，花呗分期手续hbfq，其他手续费charge
+	RefundSubFeeDetailList []struct {
+		RefundChargeFee string `json:"refund_charge_fee"` //实退费用
+		SwitchFeeRate   string `json:"switch_fee_rate"`   //签约费率
+	} `json:"refund_sub_fee_detail_list"` //组合支付退费明细
+}
+
+type RefundPresetPaytool struct {
+	Amount         []string `json:"amount"`           //前置资产金额
+	AssertTypeCode string   `json:"assert_type_code"` //前置资产类型编码
+}
+
+func (alipay *Alipay) TradeRefund(bizContent TradeRefundParams) (*TradeRefundResult, string, error) {
+	if bizContent.OutTradeNo == "" && bizContent.TradeNo == "" {
+		return nil, "", errors.New("outTradeNo和tradeNo必须填写一项")
+	}
+	if bizContent.RefundAmount == "" {
+		return nil, "", errors.New("refundAmount未填写")
+	}
+	var result TradeRefundResult
+	data, err := alipay.Request(MethodTradeRefund, bizContent, &result)
+	return &result, data, err
+}
+
+//统一收单交易退款查询
+type TradeFastpayRefundQueryParams struct {
+	OutTradeNo   string   `json:"out_trade_no,omitempty"`  //商户订单号 与trade_no二选一
+	TradeNo      string   `json:"trade_no,omitempty"`      //支付宝交易号 与out_trade_no二选一
+	OutRequestNo string   `json:"out_request_no"`          //退款请求号 必填 未传入时为out_trade_no
+	OrgPid       string   `json:"org_pid,omitempty"`       //银行间联模式下有用
+	QueryOptions []string `json:"query_options,omitempty"` //查询选项 如refund_detail_item_list、gmt_refund_pay
+}
+
+type TradeFastpayRefundQueryResult struct {
+	Result
+	TradeNo              string             `json:"trade_no"`                //支付宝交易号
+	OutTradeNo           string             `json:"out_trade_no"`            //商户订单号
+	OutRequestNo         string             `json:"out_request_no"`          //退款请求号
+	TotalAmount          string             `json:"total_amount"`            //交易的订单金额
+	RefundAmount         string             `json:"refund_amount"`           //本次退款请求对应的退款金额
+	RefundStatus         string             `json:"refund_status"`           //退款状态 REFUND_SUCCESS为退款处理成功
+	RefundRoyaltys       []RefundRoyalty    `json:"refund_royaltys"`         //退分账明细信息
+	GmtRefundPay         string             `json:"gmt_refund_pay"`          //退款时间
+	RefundDetailItemList []TradeFundBill    `json:"refund_detail_item_list"` //本次退款使用的资金渠道
+	SendBackFee          string             `json:"send_back_fee"`           //本次商户实际退回金额
+	RefundHybAmount      string             `json:"refund_hyb_amount"`       //本次请求退惠营宝金额
+	RefundChargeInfoList []RefundChargeInfo `json:"refund_charge_info_list"` //退费信息
+}
+
+type RefundRoyalty struct {
+	RefundAmount  string `json:"refund_amount"`   //退分账金额
+	RoyaltyType   string `json:"royalty_type"`    //分账类型
+	ResultCode    string `json:"result_code"`     //退分账结果码
+	TransOut      string `json:"trans_out"`       //转出人支付宝账号对应用户ID
+	TransOutEmail string `json:"trans_out_email"` //转出人支付宝账号
+	TransIn       string `json:"trans_in"`        //转入人支付宝账号对应用户ID
+	TransInEmail  string `json:"trans_in_email"`  //转入人支付宝账号
+}
+
+func (alipay *Alipay) TradeFastpayRefundQuery(bizContent TradeFastpayRefundQueryParams) (*TradeFastpayRefundQueryResult, string, error) {
+	if bizContent.OutTradeNo == "" && bizContent.TradeNo == "" {
+		return nil, "", errors.New("outTradeNo和tradeNo必须填写一项")
+	}
+	if bizContent.OutRequestNo == "" {
+		return nil, "", errors.New("outRequestNo未填写")
+	}
+	var result TradeFastpayRefundQueryResult
+	data, err := alipay.Request(MethodTradeFastpayRefundQuery, bizContent, &result)
+	return &result, data, err
+}
@@ -0,0 +1,73 @@
+package alipay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+//支付宝响应中参与验签的节点
+type SignedResponse struct {
+	NodeName     string //节点名称 xxx_response或error_response
+	Content      []byte //节点的原始内容，即验签内容
+	Sign         string //签名 未返回时为空
+	AlipayCertSn string //公钥证书模式下支付宝公钥证书序列号
+}
+
+//逐个读取响应JSON的顶层字段，取出nodeName或error_response节点的原始内容以及sign、alipay_cert_sn
+func (alipay *Alipay) ParseJsonResponse(body []byte, nodeName string) (*SignedResponse, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, errors.New("response error: invalid json")
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("response error: not a json object")
+	}
+	var response SignedResponse
+	var errorContent json.RawMessage
+	for decoder.More() {
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, errors.New("response error: invalid json")
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, errors.New("response error: invalid json")
+		}
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return nil, errors.New("response error: invalid json")
+		}
+		switch key {
+		case nodeName:
+			response.Content = value
+		case alipay.errorResponse:
+			errorContent = value
+		case alipay.signNodeName:
+			if err = json.Unmarshal(value, &response.Sign); err != nil {
+				return nil, errors.New("response error: invalid sign")
+			}
+		case "alipay_cert_sn":
+			if err = json.Unmarshal(value, &response.AlipayCertSn); err != nil {
+				return nil, errors.New("response error: invalid alipay_cert_sn")
+			}
+		}
+	}
+	if _, err = decoder.Token(); err != nil {
+		return nil, errors.New("response error: invalid json")
+	}
+	if _, err = decoder.Token(); err != io.EOF {
+		return nil, errors.New("response error: unexpected data after json object")
+	}
+	if response.Content != nil {
+		response.NodeName = nodeName
+	} else if errorContent != nil {
+		response.NodeName = alipay.errorResponse
+		response.Content = errorContent
+	} else {
+		return nil, errors.New("response error: " + nodeName + " not found")
+	}
+	return &response, nil
+}
@@ -0,0 +1,153 @@
+package alipay
+
+import "errors"
+
+//结算信息 交易需要结算到指定账户时传入
+type SettleInfo struct {
+	SettleDetailInfos []SettleDetailInfo `json:"settle_detail_infos"`          //结算详细信息 必填
+	SettlePeriodTime  string             `json:"settle_period_time,omitempty"` //该笔订单的超期自动确认结算时间
+}
+
+type SettleDetailInfo struct {
+	TransInType      string `json:"trans_in_type"`                //结算收款方的账户类型 cardAliasNo/userId/loginName/defaultSettle 必填
+	TransIn          string `json:"trans_in"`                     //结算收款方 必填
+	SummaryDimension string `json:"summary_dimension,omitempty"`  //结算汇总维度
+	SettleEntityId   string `json:"settle_entity_id,omitempty"`   //结算主体标识
+	SettleEntityType string `json:"settle_entity_type,omitempty"` //结算主体类型
+	Amount           string `json:"amount"`                       //结算的金额 必填
+}
+
+//分账明细
+type RoyaltyParameters struct {
+	RoyaltyType  string `json:"royalty_type,omitempty"`   //分账类型 transfer/replenish
+	TransOut     string `json:"trans_out,omitempty"`      //支出方账户
+	TransOutType string `json:"trans_out_type,omitempty"` //支出方账户类型 userId/loginName
+	TransInType  string `json:"trans_in_type,omitempty"`  //收入方账户类型 userId/cardAliasNo/loginName
+	TransIn      string `json:"trans_in"`                 //收入方账户 必填
+	Amount       string `json:"amount,omitempty"`         //分账的金额
+	Desc         string `json:"desc,omitempty"`           //分账描述
+	RoyaltyScene string `json:"royalty_scene,omitempty"`  //可选值：达人佣金、平台服务费、技术服务费、其他
+	TransInName  string `json:"trans_in_name,omitempty"`  //分账收款方姓名
+}
+
+//分账关系
+type RoyaltyEntity struct {
+	Type          string `json:"type"`                      //分账接收方方类型 userId/loginName/openId 必填
+	Account       string `json:"account"`                   //分账接收方账号 必填
+	AccountOpenId string `json:"account_open_id,omitempty"` //分账接收方账号openId
+	Name          string `json:"name,omitempty"`            //分账接收方真实姓名
+	Memo          string `json:"memo,omitempty"`            //分账关系描述
+	LoginName     string `json:"login_name,omitempty"`      //作为分账接收方的支付宝账号对应的登录号
+	BindLoginName string `json:"bind_login_name,omitempty"` //被授权方的支付宝登录号
+}
+
+//分账关系绑定与解绑
+type TradeRoyaltyRelationParams struct {
+	ReceiverList []RoyaltyEntity `json:"receiver_list"`  //分账接收方列表 必填
+	OutRequestNo string          `json:"out_request_no"` //外部请求号 必填
+}
+
+type TradeRoyaltyRelationResult struct {
+	Result
+	ResultCode string `json:"result_code"` //SUCCESS/FAIL
+}
+
+func (params TradeRoyaltyRelationParams) check() error {
+	if len(params.ReceiverList) == 0 {
+		return errors.New("receiverList未填写")
+	}
+	if params.OutRequestNo == "" {
+		return errors.New("outRequestNo未填写")
+	}
+	return nil
+}
+
+func (alipay *Alipay) TradeRoyaltyRelationBind(bizContent TradeRoyaltyRelationParams) (*TradeRoyaltyRelationResult, string, error) {
+	if err := bizContent.check(); err != nil {
+		return nil, "", err
+	}
+	var result TradeRoyaltyRelationResult
+	data, err := alipay.Request(MethodTradeRoyaltyRelationBind, bizContent, &result)
+	return &result, data, err
+}
+
+func (alipay *Alipay) TradeRoyaltyRelationUnbind(bizContent TradeRoyaltyRelationParams) (*TradeRoyaltyRelationResult, string, error) {
+	if err := bizContent.check(); err != nil {
+		return nil, "", err
+	}
+	var result TradeRoyaltyRelationResult
+	data, err := alipay.Request(MethodTradeRoyaltyRelationUnbind, bizContent, &result)
+	return &result, data, err
+}
+
+//统一收单交易结算
+type TradeOrderSettleParams struct {
+	OutRequestNo      string              `json:"out_request_no"`          //结算请求流水号 必填
+	TradeNo           string              `json:"trade_no"`                //支付宝交易号 必填
+	RoyaltyParameters []RoyaltyParameters `json:"royalty_parameters"`      //分账明细信息 必填
+	OperatorId        string              `json:"operator_id,omitempty"`   //操作员id
+	ExtendParams      *SettleExtendParams `json:"extend_params,omitempty"` //分账结算扩展参数
+	RoyaltyMode       string              `json:"royalty_mode,omitempty"`  //分账模式 sync/async
+}
+
+type SettleExtendParams struct {
+	RoyaltyFinish string `json:"royalty_finish,omitempty"` //是否分账完结 true/false
+}
+
+type TradeOrderSettleResult struct {
+	Result
+	TradeNo  string `json:"trade_no"`  //支付宝交易号
+	SettleNo string `json:"settle_no"` //支付宝分账单号
+}
+
+func (alipay *Alipay) TradeOrderSettle(bizContent TradeOrderSettleParams) (*TradeOrderSettleResult, string, error) {
+	if bizContent.OutRequestNo == "" {
+		return nil, "", errors.New("outRequestNo未填写")
+	}
+	if bizContent.TradeNo == "" {
+		return nil, "", errors.New("tradeNo未填写")
+	}
+	if len(bizContent.RoyaltyParameters) == 0 {
+		return nil, "", errors.New("royaltyParameters未填写")
+	}
+	var result TradeOrderSettleResult
+	data, err := alipay.Request(MethodTradeOrderSettle, bizContent, &result)
+	return &result, data, err
+}
+
+//交易分账查询
+type TradeOrderSettleQueryParams struct {
+	SettleNo     string `json:"settle_no,omitempty"`      //支付宝分账请求单号 与out_request_no+trade_no二选一
+	OutRequestNo string `json:"out_request_no,omitempty"` //外部请求号
+	TradeNo      string `json:"trade_no,omitempty"`       //支付宝交易号
+}
+
+type TradeOrderSettleQueryResult struct {
+	Result
+	OutRequestNo      string              `json:"out_request_no"`      //外部请求号
+	OperationDt       string              `json:"operation_dt"`        //分账受理时间
+	RoyaltyDetailList []RoyaltyDetailInfo `json:"royalty_detail_list"` //分账明细
+}
+
+type RoyaltyDetailInfo struct {
+	OperationType string `json:"operation_type"` //分账操作类型 replenish/replenish_refund/transfer/transfer_refund
+	ExecuteDt     string `json:"execute_dt"`     //分账执行时间
+	TransOut      string `json:"trans_out"`      //分账转出账号
+	TransOutType  string `json:"trans_out_type"` //分账转出账号类型
+	TransIn       string `json:"trans_in"`       //分账转入账号
+	TransInType   string `json:"trans_in_type"`  //分账转入账号类型
+	Amount        string `json:"amount"`         //分账金额
+	State         string `json:"state"`          //分账状态 SUCCESS/FAIL/PROCESSING
+	DetailId      string `json:"detail_id"`      //分账明细单号
+	ErrorCode     string `json:"error_code"`     //分账失败错误码
+	ErrorDesc     string `json:"error_desc"`     //分账错误描述信息
+}
+
+func (alipay *Alipay) TradeOrderSettleQuery(bizContent TradeOrderSettleQueryParams) (*TradeOrderSettleQueryResult, string, error) {
+	if bizContent.SettleNo == "" && (bizContent.OutRequestNo == "" || bizContent.TradeNo == "") {
+		return nil, "", errors.New("settleNo或outRequestNo和tradeNo必须填写")
+	}
+	var result TradeOrderSettleQueryResult
+	data, err := alipay.Request(MethodTradeOrderSettleQuery, bizContent, &result)
+	return &result, data, err
+}
@@ -1,21 +1,25 @@
 package alipay
 
+import "errors"
+
 type TradePreCreateParams struct {
-	OutTradeNo           string `json:"out_trade_no"`            //订单号 必填
-	TotalAmount          string `json:"total_amount"`            //订单金额 必填
-	Subject              string `json:"subject"`                 //订单标题 必填
-	SellerId             string `json:"seller_id"`               //支付宝用户ID
-	DiscountableAmount   string `json:"discountable_amount"`     //可打折金额
-	Body                 string `json:"body"`                    //对商品的描述
-	ProductCode          string `json:"product_code"`            //销售产品码
-	OperatorId           string `json:"operator_id"`             //商户操作员编码
-	StoreId              string `json:"store_id"`                //商户门店编码
-	DisablePayChannels   string `json:"disable_pay_channels"`    //禁止渠道
-	EnablePayChannels    string `json:"enable_pay_channels"`     //可用渠道
-	TerminalId           string `json:"terminal_id"`             //终端id
-	TimeoutExpress       string `json:"timeout_express"`         //该笔订单允许的最晚付款时间，逾期将关闭交易
-	MerchantOrderNo      string `json:"merchant_order_no"`       //商户原始订单号
-	QrCodeTimeoutExpress string `json:"qr_code_timeout_express"` //该笔订单最晚付款时间
+	OutTradeNo           string        `json:"out_trade_no"`            //订单号 必填
+	TotalAmount          string        `json:"total_amount"`            //订单金额 必填
+	Subject              string        `json:"subject"`                 //订单标题 必填
+	SellerId             string        `json:"seller_id"`               //支付宝用户ID
+	DiscountableAmount   string        `json:"discountable_amount"`     //可打折金额
+	Body                 string        `json:"body"`                    //对商品的描述
+	ProductCode          string        `json:"product_code"`            //销售产品码
+	OperatorId           string        `json:"operator_id"`             //商户操作员编码
+	StoreId              string        `json:"store_id"`                //商户门店编码
+	DisablePayChannels   string        `json:"disable_pay_channels"`    //禁止渠道
+	EnablePayChannels    string        `json:"enable_pay_channels"`     //可用渠道
+	TerminalId           string        `json:"terminal_id"`             //终端id
+	TimeoutExpress       string        `json:"timeout_express"`         //该笔订单允许的最晚付款时间，逾期将关闭交易
+	MerchantOrderNo      string        `json:"merchant_order_no"`       //商户原始订单号
+	QrCodeTimeoutExpress string        `json:"qr_code_timeout_express"` //该笔订单最晚付款时间
+	ExtendParams         *ExtendParams `json:"extend_params,omitempty"` //业务扩展参数
+	SettleInfo           *SettleInfo   `json:"settle_info,omitempty"`   //描述结算信息
 }
 
 type TradePreCreateResult struct {
@@ -29,3 +33,56 @@ func (alipay *Alipay) TradePreCreate(bizContent TradePreCreateParams) (*TradePre
 	data, err := alipay.Request(MethodTradePreCreate, bizContent, &result)
 	return &result, data, err
 }
+
+//统一收单交易创建（小程序JSAPI支付）
+type TradeCreateParams struct {
+	OutTradeNo         string        `json:"out_trade_no"`                   //订单号 必填
+	TotalAmount        string        `json:"total_amount"`                   //订单金额 必填
+	Subject            string        `json:"subject"`                        //订单标题 必填
+	ProductCode        string        `json:"product_code,omitempty"`         //销售产品码 JSAPI_PAY
+	BuyerId            string        `json:"buyer_id,omitempty"`             //买家支付宝用户ID 与buyer_open_id二选一
+	BuyerOpenId        string        `json:"buyer_open_id,omitempty"`        //买家支付宝用户唯一标识 与buyer_id二选一
+	OpAppId            string        `json:"op_app_id,omitempty"`            //小程序支付中，商户实际经营主体的小程序应用的appid
+	SellerId           string        `json:"seller_id,omitempty"`            //卖家支付宝用户ID
+	DiscountableAmount string        `json:"discountable_amount,omitempty"`  //可打折金额
+	Body               string        `json:"body,omitempty"`                 //对商品的描述
+	GoodsDetail        []GoodsDetail `json:"goods_detail,omitempty"`         //订单包含的商品列表信息
+	OperatorId         string        `json:"operator_id,omitempty"`          //商户操作员编码
+	StoreId            string        `json:"store_id,omitempty"`             //商户门店编码
+	TerminalId         string        `json:"terminal_id,omitempty"`          //终端id
+	TimeExpire         string        `json:"time_expire,omitempty"`          //订单绝对超时时间
+	TimeoutExpress     string        `json:"timeout_express,omitempty"`      //该笔订单允许的最晚付款时间
+	DisablePayChannels string        `json:"disable_pay_channels,omitempty"` //禁止渠道
+	EnablePayChannels  string        `json:"enable_pay_channels,omitempty"`  //可用渠道
+	MerchantOrderNo    string        `json:"merchant_order_no,omitempty"`    //商户原始订单号
+	PassbackParams     string        `json:"passback_params,omitempty"`      //公用回传参数
+	ExtendParams       *ExtendParams `json:"extend_params,omitempty"`        //业务扩展参数
+	SettleInfo         *SettleInfo   `json:"settle_info,omitempty"`          //描述结算信息
+}
+
+type TradeCreateResult struct {
+	Result
+	OutTradeNo string `json:"out_trade_no"` //商户订单号
+	TradeNo    string `json:"trade_no"`     //支付宝交易号 用于my.tradePay
+}
+
+func (alipay *Alipay) TradeCreate(bizContent TradeCreateParams) (*TradeCreateResult, string, error) {
+	if bizContent.OutTradeNo == "" {
+		return nil, "", errors.New("outTradeNo未填写")
+	}
+	if bizContent.TotalAmount == "" {
+		return nil, "", errors.New("totalAmount未填写")
+	}
+	if bizContent.Subject == "" {
+		return nil, "", errors.New("subject未填写")
+	}
+	if bizContent.BuyerId == "" && bizContent.BuyerOpenId == "" {
+		return nil, "", errors.New("buyerId和buyerOpenId必须填写一项")
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodeJsapiPay
+	}
+	var result TradeCreateResult
+	data, err := alipay.Request(MethodTradeCreate, bizContent, &result)
+	return &result, data, err
+}
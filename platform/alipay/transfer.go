@@ -0,0 +1,139 @@
+package alipay
+
+import "errors"
+
+var errCertModeRequired = errors.New("资金类接口必须使用公钥证书模式")
+
+//单笔转账
+type FundTransUniTransferParams struct {
+	OutBizNo        string         `json:"out_biz_no"`                  //商家侧唯一订单号 必填
+	TransAmount     string         `json:"trans_amount"`                //订单总金额 必填
+	ProductCode     string         `json:"product_code"`                //业务产品码 TRANS_ACCOUNT_NO_PWD/TRANS_BANKCARD_NO_PWD 必填
+	BizScene        string         `json:"biz_scene"`                   //业务场景 DIRECT_TRANSFER
+	OrderTitle      string         `json:"order_title,omitempty"`       //转账业务的标题
+	OriginalOrderId string         `json:"original_order_id,omitempty"` //原支付宝业务单号
+	PayeeInfo       TransPayeeInfo `json:"payee_info"`                  //收款方信息 必填
+	Remark          string         `json:"remark,omitempty"`            //业务备注
+	BusinessParams  string         `json:"business_params,omitempty"`   //转账业务请求的扩展参数
+	PassbackParams  string         `json:"passback_params,omitempty"`   //公用回传参数
+}
+
+type TransPayeeInfo struct {
+	Identity        string           `json:"identity"`                    //参与方的唯一标识 必填
+	IdentityType    string           `json:"identity_type"`               //参与方的标识类型 ALIPAY_USER_ID/ALIPAY_LOGON_ID/BANKCARD_ACCOUNT 必填
+	Name            string           `json:"name,omitempty"`              //参与方真实姓名 ALIPAY_LOGON_ID和BANKCARD_ACCOUNT时必填
+	BankcardExtInfo *BankcardExtInfo `json:"bankcard_ext_info,omitempty"` //银行卡信息 转账到银行卡时必填
+}
+
+type BankcardExtInfo struct {
+	InstName       string `json:"inst_name"`                  //机构名称 必填
+	AccountType    string `json:"account_type"`               //收款账户类型 1对公 2对私 必填
+	InstProvince   string `json:"inst_province,omitempty"`    //银行所在省份
+	InstCity       string `json:"inst_city,omitempty"`        //收款银行所在市
+	InstBranchName string `json:"inst_branch_name,omitempty"` //收款银行所属支行
+	BankCode       string `json:"bank_code,omitempty"`        //银行支行联行号
+}
+
+type FundTransUniTransferResult struct {
+	Result
+	OutBizNo       string `json:"out_biz_no"`        //商户订单号
+	OrderId        string `json:"order_id"`          //支付宝转账订单号
+	PayFundOrderId string `json:"pay_fund_order_id"` //支付宝支付资金流水号
+	Status         string `json:"status"`            //转账单据状态 SUCCESS/DEALING/FAIL/REFUND
+	TransDate      string `json:"trans_date"`        //订单支付时间
+	SettleSerialNo string `json:"settle_serial_no"`  //清算机构流水号
+}
+
+func (alipay *Alipay) FundTransUniTransfer(bizContent FundTransUniTransferParams) (*FundTransUniTransferResult, string, error) {
+	if !alipay.IsCertMode() {
+		return nil, "", errCertModeRequired
+	}
+	if bizContent.OutBizNo == "" {
+		return nil, "", errors.New("outBizNo未填写")
+	}
+	if bizContent.TransAmount == "" {
+		return nil, "", errors.New("transAmount未填写")
+	}
+	if bizContent.PayeeInfo.Identity == "" || bizContent.PayeeInfo.IdentityType == "" {
+		return nil, "", errors.New("payeeInfo未填写")
+	}
+	if bizContent.ProductCode == "" {
+		if bizContent.PayeeInfo.IdentityType == IdentityTypeBankcardAccount {
+			bizContent.ProductCode = ProductCodeTransBankcardNoPwd
+		} else {
+			bizContent.ProductCode = ProductCodeTransAccountNoPwd
+		}
+	}
+	if bizContent.ProductCode == ProductCodeTransBankcardNoPwd && bizContent.PayeeInfo.BankcardExtInfo == nil {
+		return nil, "", errors.New("bankcardExtInfo未填写")
+	}
+	if bizContent.BizScene == "" {
+		bizContent.BizScene = BizSceneDirectTransfer
+	}
+	var result FundTransUniTransferResult
+	data, err := alipay.Request(MethodFundTransUniTransfer, bizContent, &result)
+	return &result, data, err
+}
+
+//转账业务单据查询
+type FundTransCommonQueryParams struct {
+	ProductCode    string `json:"product_code,omitempty"`      //业务产品码
+	BizScene       string `json:"biz_scene,omitempty"`         //业务场景
+	OutBizNo       string `json:"out_biz_no,omitempty"`        //商户转账唯一订单号
+	OrderId        string `json:"order_id,omitempty"`          //支付宝转账单据号
+	PayFundOrderId string `json:"pay_fund_order_id,omitempty"` //支付宝支付资金流水号
+}
+
+type FundTransCommonQueryResult struct {
+	Result
+	OrderId        string `json:"order_id"`          //支付宝转账单据号
+	PayFundOrderId string `json:"pay_fund_order_id"` //支付宝支付资金流水号
+	OutBizNo       string `json:"out_biz_no"`        //商户订单号
+	TransAmount    string `json:"trans_amount"`      //付款金额
+	Status         string `json:"status"`            //转账单据状态
+	PayDate        string `json:"pay_date"`          //支付时间
+	ArrivalTimeEnd string `json:"arrival_time_end"`  //预计到账时间
+	OrderFee       string `json:"order_fee"`         //预计收费金额
+	ErrorCode      string `json:"error_code"`        //查询到的订单状态为FAIL失败或REFUND退票时，返回错误代码
+	FailReason     string `json:"fail_reason"`       //查询到的订单状态为FAIL失败或REFUND退票时，返回具体的原因
+	SubStatus      string `json:"sub_status"`        //退票子状态
+}
+
+func (alipay *Alipay) FundTransCommonQuery(bizContent FundTransCommonQueryParams) (*FundTransCommonQueryResult, string, error) {
+	if !alipay.IsCertMode() {
+		return nil, "", errCertModeRequired
+	}
+	if bizContent.OutBizNo == "" && bizContent.OrderId == "" && bizContent.PayFundOrderId == "" {
+		return nil, "", errors.New("outBizNo、orderId和payFundOrderId必须填写一项")
+	}
+	var result FundTransCommonQueryResult
+	data, err := alipay.Request(MethodFundTransCommonQuery, bizContent, &result)
+	return &result, data, err
+}
+
+//支付宝资金账户资产查询
+type FundAccountQueryParams struct {
+	AlipayUserId string `json:"alipay_user_id"`         //支付宝会员id 必填
+	AccountType  string `json:"account_type,omitempty"` //查询的账号类型 ACCTRANS_ACCOUNT
+}
+
+type FundAccountQueryResult struct {
+	Result
+	AvailableAmount string `json:"available_amount"` //账户可用余额
+	FreezeAmount    string `json:"freeze_amount"`    //冻结金额
+}
+
+func (alipay *Alipay) FundAccountQuery(bizContent FundAccountQueryParams) (*FundAccountQueryResult, string, error) {
+	if !alipay.IsCertMode() {
+		return nil, "", errCertModeRequired
+	}
+	if bizContent.AlipayUserId == "" {
+		return nil, "", errors.New("alipayUserId未填写")
+	}
+	if bizContent.AccountType == "" {
+		bizContent.AccountType = AccountTypeAcctransAccount
+	}
+	var result FundAccountQueryResult
+	data, err := alipay.Request(MethodFundAccountQuery, bizContent, &result)
+	return &result, data, err
+}
@@ -0,0 +1,65 @@
+package alipay
+
+import "errors"
+
+//手机网站支付
+type TradeWapPayParams struct {
+	OutTradeNo         string        `json:"out_trade_no"`                   //订单号 必填
+	TotalAmount        string        `json:"total_amount"`                   //订单金额 必填
+	Subject            string        `json:"subject"`                        //订单标题 必填
+	ProductCode        string        `json:"product_code"`                   //销售产品码 QUICK_WAP_WAY
+	QuitUrl            string        `json:"quit_url,omitempty"`             //用户付款中途退出返回商户网站的地址
+	Body               string        `json:"body,omitempty"`                 //对商品的描述
+	GoodsDetail        []GoodsDetail `json:"goods_detail,omitempty"`         //订单包含的商品列表信息
+	TimeExpire         string        `json:"time_expire,omitempty"`          //订单绝对超时时间
+	TimeoutExpress     string        `json:"timeout_express,omitempty"`      //该笔订单允许的最晚付款时间
+	AuthToken          string        `json:"auth_token,omitempty"`           //针对用户授权接口，获取用户相关数据时，用于标识用户授权关系
+	GoodsType          string        `json:"goods_type,omitempty"`           //商品主类型 0虚拟类商品 1实物类商品
+	PassbackParams     string        `json:"passback_params,omitempty"`      //公用回传参数
+	EnablePayChannels  string        `json:"enable_pay_channels,omitempty"`  //可用渠道
+	DisablePayChannels string        `json:"disable_pay_channels,omitempty"` //禁用渠道
+	StoreId            string        `json:"store_id,omitempty"`             //商户门店编号
+	MerchantOrderNo    string        `json:"merchant_order_no,omitempty"`    //商户原始订单号
+	ReturnUrl          string        `json:"-"`                              //支付完成后的同步跳转地址
+	ExtendParams       *ExtendParams `json:"extend_params,omitempty"`        //业务扩展参数
+	SettleInfo         *SettleInfo   `json:"settle_info,omitempty"`          //描述结算信息
+}
+
+func (params TradeWapPayParams) check() error {
+	if params.OutTradeNo == "" {
+		return errors.New("outTradeNo未填写")
+	}
+	if params.TotalAmount == "" {
+		return errors.New("totalAmount未填写")
+	}
+	if params.Subject == "" {
+		return errors.New("subject未填写")
+	}
+	return nil
+}
+
+func (params TradeWapPayParams) extParams() map[string]string {
+	return returnUrlParams(params.ReturnUrl)
+}
+
+//手机网站支付 返回GET跳转地址
+func (alipay *Alipay) TradeWapPayURL(bizContent TradeWapPayParams) (string, error) {
+	if err := bizContent.check(); err != nil {
+		return "", err
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodeQuickWapWay
+	}
+	return alipay.BuildPageURL(MethodTradeWapPay, bizContent, bizContent.extParams())
+}
+
+//手机网站支付 返回自动提交的POST表单
+func (alipay *Alipay) TradeWapPayForm(bizContent TradeWapPayParams) (string, error) {
+	if err := bizContent.check(); err != nil {
+		return "", err
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodeQuickWapWay
+	}
+	return alipay.BuildPageForm(MethodTradeWapPay, bizContent, bizContent.extParams())
+}
@@ -0,0 +1,76 @@
+package alipay
+
+import "errors"
+
+//电脑网站支付
+type TradePagePayParams struct {
+	OutTradeNo         string        `json:"out_trade_no"`                   //订单号 必填
+	TotalAmount        string        `json:"total_amount"`                   //订单金额 必填
+	Subject            string        `json:"subject"`                        //订单标题 必填
+	ProductCode        string        `json:"product_code"`                   //销售产品码 FAST_INSTANT_TRADE_PAY
+	Body               string        `json:"body,omitempty"`                 //对商品的描述
+	GoodsDetail        []GoodsDetail `json:"goods_detail,omitempty"`         //订单包含的商品列表信息
+	TimeExpire         string        `json:"time_expire,omitempty"`          //订单绝对超时时间
+	TimeoutExpress     string        `json:"timeout_express,omitempty"`      //该笔订单允许的最晚付款时间
+	GoodsType          string        `json:"goods_type,omitempty"`           //商品主类型 0虚拟类商品 1实物类商品
+	PassbackParams     string        `json:"passback_params,omitempty"`      //公用回传参数
+	EnablePayChannels  string        `json:"enable_pay_channels,omitempty"`  //可用渠道
+	DisablePayChannels string        `json:"disable_pay_channels,omitempty"` //禁用渠道
+	StoreId            string        `json:"store_id,omitempty"`             //商户门店编号
+	MerchantOrderNo    string        `json:"merchant_order_no,omitempty"`    //商户原始订单号
+	QrPayMode          string        `json:"qr_pay_mode,omitempty"`          //PC扫码支付的方式 0/1/2/3/4
+	QrcodeWidth        string        `json:"qrcode_width,omitempty"`         //商户自定义二维码宽度 qr_pay_mode=4时有效
+	IntegrationType    string        `json:"integration_type,omitempty"`     //请求后页面的集成方式 ALIAPP/PCWEB
+	RequestFromUrl     string        `json:"request_from_url,omitempty"`     //请求来源地址
+	ReturnUrl          string        `json:"-"`                              //支付完成后的同步跳转地址
+	ExtendParams       *ExtendParams `json:"extend_params,omitempty"`        //业务扩展参数
+	SettleInfo         *SettleInfo   `json:"settle_info,omitempty"`          //描述结算信息
+}
+
+func (params TradePagePayParams) check() error {
+	if params.OutTradeNo == "" {
+		return errors.New("outTradeNo未填写")
+	}
+	if params.TotalAmount == "" {
+		return errors.New("totalAmount未填写")
+	}
+	if params.Subject == "" {
+		return errors.New("subject未填写")
+	}
+	return nil
+}
+
+func (params TradePagePayParams) extParams() map[string]string {
+	return returnUrlParams(params.ReturnUrl)
+}
+
+//return_url未填写时不传该参数
+func returnUrlParams(returnUrl string) map[string]string {
+	extParams := map[string]string{}
+	if returnUrl != "" {
+		extParams["return_url"] = returnUrl
+	}
+	return extParams
+}
+
+//电脑网站支付 返回GET跳转地址
+func (alipay *Alipay) TradePagePayURL(bizContent TradePagePayParams) (string, error) {
+	if err := bizContent.check(); err != nil {
+		return "", err
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodeFastInstantTradePay
+	}
+	return alipay.BuildPageURL(MethodTradePagePay, bizContent, bizContent.extParams())
+}
+
+//电脑网站支付 返回自动提交的POST表单
+func (alipay *Alipay) TradePagePayForm(bizContent TradePagePayParams) (string, error) {
+	if err := bizContent.check(); err != nil {
+		return "", err
+	}
+	if bizContent.ProductCode == "" {
+		bizContent.ProductCode = ProductCodeFastInstantTradePay
+	}
+	return alipay.BuildPageForm(MethodTradePagePay, bizContent, bizContent.extParams())
+}
@@ -2,12 +2,19 @@ package utils
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
+//接口请求的默认客户端，避免连接挂起时无限等待
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
 func HttpPost(URL string, contentType string, rawBody []byte) ([]byte, error) {
-	resp, err := http.Post(URL, contentType, bytes.NewReader(rawBody))
+	resp, err := httpClient.Post(URL, contentType, bytes.NewReader(rawBody))
 	if err != nil {
 		return nil, err
 	}
@@ -16,10 +23,32 @@ func HttpPost(URL string, contentType string, rawBody []byte) ([]byte, error) {
 }
 
 func HttpGet(URL string) ([]byte, error) {
-	resp, err := http.Get(URL)
+	return HttpGetContext(context.Background(), URL)
+}
+
+//ctx取消或超时时中断请求
+func HttpGetContext(ctx context.Context, URL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	return ioutil.ReadAll(resp.Body)
 }
+
+func HttpDownload(URL string, w io.Writer) error {
+	resp, err := http.Get(URL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("download failed: " + resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}